@@ -20,6 +20,8 @@
 package main
 
 import (
+	"context"
+	"io"
 	"os"
 	"os/signal"
 	"sync"
@@ -31,59 +33,160 @@ import (
 
 	"github.com/sapcc/kube-fip-controller/pkg/config"
 	"github.com/sapcc/kube-fip-controller/pkg/controller"
+	"github.com/sapcc/kube-fip-controller/pkg/health"
 	"github.com/sapcc/kube-fip-controller/pkg/metrics"
 	"github.com/sapcc/kube-fip-controller/pkg/version"
 )
 
 const programName = "kube-fip-controller"
 
-var opts config.Options
+const (
+	auditOutputTable = "table"
+	auditOutputJSON  = "json"
+)
+
+var (
+	opts        config.Options
+	auditOutput string
+	auditCmd    *kingpin.CmdClause
+)
 
 func init() {
+	kingpin.Command("run", "Run the controller.").Default()
+	auditCmd = kingpin.Command("audit", "Report drift between nodes and FIPs in OpenStack without changing anything. Exits non-zero on drift.")
+	auditCmd.Flag("output", "Output format of the report: table or json.").Short('o').Default(auditOutputTable).EnumVar(&auditOutput, auditOutputTable, auditOutputJSON)
+
 	kingpin.Flag("kubeconfig", "Absolute path to kubeconfig").StringVar(&opts.KubeConfig)
 	kingpin.Flag("debug", "Enable debug logging").Default("false").BoolVar(&opts.IsDebug)
 	kingpin.Flag("threadiness", "The controllers threadiness").Default("1").IntVar(&opts.Threadiness)
 	kingpin.Flag("recheck-interval", "Interval for checking with OpenStack.").Default("10m").DurationVar(&opts.RecheckInterval)
+	kingpin.Flag("max-retries", "Number of retries of a failed node sync before waiting for the next recheck. Permanent errors are not retried.").Default("5").IntVar(&opts.MaxRetries)
+	kingpin.Flag("retry-base-delay", "Delay before the first retry of a failed node sync. Doubled with every retry.").Default("30s").DurationVar(&opts.RetryBaseDelay)
+	kingpin.Flag("retry-max-delay", "Maximum delay between retries of a failed node sync.").Default("10m").DurationVar(&opts.RetryMaxDelay)
+	kingpin.Flag("sync-timeout", "Maximum duration of a single node sync. 0 disables the timeout.").Default("2m").DurationVar(&opts.SyncTimeout)
+	kingpin.Flag("shutdown-timeout", "Maximum duration to wait for in-flight node syncs on shutdown before cancelling them.").Default("30s").DurationVar(&opts.ShutdownTimeout)
+	kingpin.Flag("openstack-timeout", "Timeout for a single request to the OpenStack APIs. 0 disables the timeout.").Default("30s").DurationVar(&opts.OpenStackTimeout)
+	kingpin.Flag("kubernetes-timeout", "Timeout for a single request to the Kubernetes API. Watches are not affected. 0 disables the timeout.").Default("30s").DurationVar(&opts.KubernetesTimeout)
 	kingpin.Flag("metric-host", "The host to expose Prometheus metrics on.").Default("0.0.0.0").IPVar(&opts.MetricHost)
-	kingpin.Flag("metric-port", "The port to expose Prometheus metrics on.").Default("9091").IntVar(&opts.MetricPort)
+	kingpin.Flag("metric-port", "The port to expose Prometheus metrics and the health endpoints on.").Default("9091").IntVar(&opts.MetricPort)
+	kingpin.Flag("liveness-timeout", "Duration after which the controller is considered not alive if no work was processed although the queue is not empty.").Default("5m").DurationVar(&opts.LivenessTimeout)
 	kingpin.Flag("default-floating-network", "Name of the default Floating IP network.").Required().StringVar(&opts.DefaultFloatingNetwork)
 	kingpin.Flag("default-floating-subnet", "Name of the default Floating IP subnet.").Required().StringVar(&opts.DefaultFloatingSubnet)
-	kingpin.Flag("config", "Absolute path to configuration file.").Required().StringVar(&opts.ConfigPath)
+	kingpin.Flag("config", "Absolute path to configuration file. Required unless --os-cloud is given.").StringVar(&opts.ConfigPath)
+	kingpin.Flag("os-cloud", "Name of the cloud in the clouds.yaml to use instead of the configuration file.").Envar("OS_CLOUD").StringVar(&opts.OSCloud)
+	kingpin.Flag("os-client-config-file", "Absolute path to the clouds.yaml. The standard locations are searched if not given.").Envar("OS_CLIENT_CONFIG_FILE").StringVar(&opts.OSClientConfigFile)
+	kingpin.Flag("config-reload-interval", "Interval for checking the configuration and credential files for changes. 0 disables reloading.").Default("1m").DurationVar(&opts.ConfigReloadInterval)
+	kingpin.Flag("fip-deletion-policy", "What to do with the FIP of a deleted node: keep, disassociate or delete.").Default(config.FIPDeletionPolicyKeep).EnumVar(&opts.FIPDeletionPolicy, config.FIPDeletionPolicyKeep, config.FIPDeletionPolicyDisassociate, config.FIPDeletionPolicyDelete)
+	kingpin.Flag("enable-finalizer", "Add a finalizer to nodes, so the FIP is released according to the deletion policy before the node is deleted.").Default("false").BoolVar(&opts.EnableFinalizer)
+	kingpin.Flag("gc-interval", "Interval for collecting orphaned FIPs allocated by the controller. 0 disables the garbage collection.").Default("0").DurationVar(&opts.GCInterval)
+	kingpin.Flag("gc-grace-period", "Minimum time since the last update of an orphaned FIP before it is collected.").Default("1h").DurationVar(&opts.GCGracePeriod)
+	kingpin.Flag("cluster-name", "Name of the cluster. FIPs allocated by the controller are tagged with it. Required for the garbage collection.").StringVar(&opts.ClusterName)
+	kingpin.Flag("project-id", "ID of a project the servers of the nodes belong to. Can be given multiple times. Limits the FIPs considered by the garbage collection and the bulk reconciliation.").StringsVar(&opts.ProjectIDs)
+	kingpin.Flag("gc-dry-run", "Only report orphaned FIPs instead of deleting them.").Default("false").BoolVar(&opts.GCDryRun)
+	kingpin.Flag("network-cache-ttl", "Duration for caching the IDs of floating networks and subnets by name. 0 disables the cache.").Default("1h").DurationVar(&opts.NetworkCacheTTL)
+	kingpin.Flag("server-cache-ttl", "Duration for caching servers. 0 disables the cache.").Default("10m").DurationVar(&opts.ServerCacheTTL)
+	kingpin.Flag("negative-cache-ttl", "Duration for caching that a network, subnet or server was not found. 0 disables the negative cache.").Default("1m").DurationVar(&opts.NegativeCacheTTL)
+	kingpin.Flag("bulk-reconciliation", "List the FIPs allocated by the controller and the ports of the projects once per recheck interval and reconcile the nodes against this snapshot instead of requesting them per node.").Default("false").BoolVar(&opts.BulkReconciliation)
+	kingpin.Flag("compute-qps", "Maximum requests per second to Nova. 0 disables the limit.").Default("0").Float64Var(&opts.ComputeRateLimit.QPS)
+	kingpin.Flag("compute-burst", "Number of requests to Nova that may exceed --compute-qps.").Default("10").IntVar(&opts.ComputeRateLimit.Burst)
+	kingpin.Flag("compute-max-in-flight", "Maximum concurrent requests to Nova. 0 disables the limit.").Default("0").IntVar(&opts.ComputeRateLimit.MaxInFlight)
+	kingpin.Flag("network-qps", "Maximum requests per second to Neutron. 0 disables the limit.").Default("0").Float64Var(&opts.NetworkRateLimit.QPS)
+	kingpin.Flag("network-burst", "Number of requests to Neutron that may exceed --network-qps.").Default("10").IntVar(&opts.NetworkRateLimit.Burst)
+	kingpin.Flag("network-max-in-flight", "Maximum concurrent requests to Neutron. 0 disables the limit.").Default("0").IntVar(&opts.NetworkRateLimit.MaxInFlight)
+	kingpin.Flag("dry-run", "Only log the changes to FIPs, nodes and resources instead of applying them.").Default("false").BoolVar(&opts.DryRun)
+	kingpin.Flag("enable-floating-ip-pools", "Use FloatingIPPool resources for selecting the floating network and subnet of nodes. Requires the CRD.").Default("false").BoolVar(&opts.EnableFloatingIPPools)
+	kingpin.Flag("enable-floating-ip-claims", "Reflect the FIP assignment of every enabled node in a FloatingIPClaim resource. Requires the CRD.").Default("false").BoolVar(&opts.EnableFloatingIPClaims)
+	kingpin.Flag("leader-elect", "Enable leader election to run multiple replicas.").Default("false").BoolVar(&opts.LeaderElect)
+	kingpin.Flag("leader-elect-lease-name", "Name of the lease used for leader election.").Default(programName).StringVar(&opts.LeaderElectLeaseName)
+	kingpin.Flag("leader-elect-namespace", "Namespace of the lease used for leader election.").Envar("POD_NAMESPACE").Default("kube-system").StringVar(&opts.LeaderElectNamespace)
+	kingpin.Flag("leader-elect-lease-duration", "Duration non-leader replicas wait before trying to acquire the lease.").Default("15s").DurationVar(&opts.LeaderElectLeaseDuration)
+	kingpin.Flag("leader-elect-renew-deadline", "Duration the leader retries renewing the lease before giving up.").Default("10s").DurationVar(&opts.LeaderElectRenewDeadline)
+	kingpin.Flag("leader-elect-retry-period", "Duration replicas wait between tries of actions.").Default("2s").DurationVar(&opts.LeaderElectRetryPeriod)
 	kingpin.Version(version.Print(programName))
 }
 
 func main() {
-	kingpin.Parse()
-
-	sigs := make(chan os.Signal, 1)
-	stop := make(chan struct{})
-	defer close(stop)
-
-	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
-	wg := &sync.WaitGroup{}
+	switch kingpin.Parse() {
+	case auditCmd.FullCommand():
+		audit()
+	default:
+		run()
+	}
+}
 
+func newLogger(w io.Writer) log.Logger {
 	logLevel := level.AllowInfo()
 	if opts.IsDebug {
 		logLevel = level.AllowDebug()
 	}
 
-	logger := log.NewLogfmtLogger(os.Stdout)
+	logger := log.NewLogfmtLogger(w)
 	logger = level.NewFilter(logger, logLevel)
-	logger = log.With(logger, "ts", log.DefaultTimestampUTC, "caller", log.Caller(3))
+	return log.With(logger, "ts", log.DefaultTimestampUTC, "caller", log.Caller(3))
+}
+
+func run() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	wg := &sync.WaitGroup{}
 
-	c, err := controller.New(opts, logger)
+	logger := newLogger(os.Stdout)
+
+	c, err := controller.New(ctx, opts, logger)
 	if err != nil {
 		//nolint:errcheck
 		_ = level.Error(logger).Log("msg", "fatal error starting the controller", "err", err)
 		return
 	}
 
-	go c.Run(opts.Threadiness, stop)
-	go metrics.ServeMetrics(opts.MetricHost, opts.MetricPort, wg, stop, logger)
+	healthChecker := health.NewChecker()
+	c.AddHealthChecks(healthChecker)
+
+	go metrics.ServeMetrics(opts.MetricHost, opts.MetricPort, healthChecker, wg, ctx.Done(), logger)
 
-	<-sigs
+	// Run returns after the workers stopped or right away if the caches could not be synced.
+	c.Run(ctx, opts.Threadiness)
+	<-ctx.Done()
 	//nolint:errcheck
 	_ = level.Info(logger).Log("msg", "shutting down")
 
 	wg.Wait()
 }
+
+// audit prints the drift between nodes and FIPs. It exits with 1 if drift was found and 2 if the audit failed.
+func audit() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The report is written to stdout, so logs go to stderr.
+	logger := newLogger(os.Stderr)
+
+	report, err := auditReport(ctx, logger)
+	if err != nil {
+		//nolint:errcheck
+		_ = level.Error(logger).Log("msg", "audit failed", "err", err)
+		os.Exit(2) //nolint:gocritic
+	}
+	if report.HasDrift() {
+		os.Exit(1) //nolint:gocritic
+	}
+}
+
+func auditReport(ctx context.Context, logger log.Logger) (*controller.AuditReport, error) {
+	c, err := controller.New(ctx, opts, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := c.Audit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if auditOutput == auditOutputJSON {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteTable(os.Stdout)
+	}
+	return report, err
+}
@@ -0,0 +1,79 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FloatingIPClaimConditionReady indicates whether the FIP is assigned and associated with the node's server.
+	FloatingIPClaimConditionReady = "Ready"
+)
+
+// FloatingIPClaim reflects the FIP assignment of a node. It has the same name as the node.
+type FloatingIPClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FloatingIPClaimSpec   `json:"spec"`
+	Status FloatingIPClaimStatus `json:"status,omitempty"`
+}
+
+// FloatingIPClaimSpec is the specification of a FloatingIPClaim.
+type FloatingIPClaimSpec struct {
+	// NodeName is the name of the node.
+	NodeName string `json:"nodeName"`
+}
+
+// FloatingIPClaimStatus is the status of a FloatingIPClaim.
+type FloatingIPClaimStatus struct {
+	// FloatingIPID is the ID of the FIP.
+	FloatingIPID string `json:"floatingIPID,omitempty"`
+
+	// FloatingIP is the address of the FIP.
+	FloatingIP string `json:"floatingIP,omitempty"`
+
+	// PortID is the ID of the port the FIP is associated with.
+	PortID string `json:"portID,omitempty"`
+
+	// ServerID is the ID of the node's server.
+	ServerID string `json:"serverID,omitempty"`
+
+	// FloatingNetworkID is the ID of the floating network.
+	FloatingNetworkID string `json:"floatingNetworkID,omitempty"`
+
+	// FloatingSubnetID is the ID of the floating subnet.
+	FloatingSubnetID string `json:"floatingSubnetID,omitempty"`
+
+	// LastSyncTime is the time of the last sync that changed the status.
+	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Conditions of the claim.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// FloatingIPClaimList is a list of FloatingIPClaims.
+type FloatingIPClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []FloatingIPClaim `json:"items"`
+}
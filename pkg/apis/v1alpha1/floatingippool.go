@@ -0,0 +1,85 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReusePolicy defines whether FIPs of a pool are reused.
+type ReusePolicy string
+
+const (
+	// ReusePolicyNone always allocates a new FIP for a node.
+	ReusePolicyNone ReusePolicy = "None"
+
+	// ReusePolicyNodepool reuses unassociated FIPs previously allocated for the node's nodepool.
+	ReusePolicyNodepool ReusePolicy = "Nodepool"
+)
+
+// FloatingIPPool defines from where FIPs are allocated for the selected nodes.
+type FloatingIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FloatingIPPoolSpec   `json:"spec"`
+	Status FloatingIPPoolStatus `json:"status,omitempty"`
+}
+
+// FloatingIPPoolSpec is the specification of a FloatingIPPool.
+type FloatingIPPoolSpec struct {
+	// FloatingNetworkName is the name of the floating network.
+	FloatingNetworkName string `json:"floatingNetworkName"`
+
+	// FloatingSubnetName is the name of the floating subnet.
+	FloatingSubnetName string `json:"floatingSubnetName,omitempty"`
+
+	// FloatingIPs optionally restricts the pool to the given FIPs.
+	FloatingIPs []string `json:"floatingIPs,omitempty"`
+
+	// NodeSelector selects the nodes using this pool.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// ReusePolicy defines whether FIPs are reused. Defaults to None.
+	ReusePolicy ReusePolicy `json:"reusePolicy,omitempty"`
+}
+
+// FloatingIPPoolStatus is the status of a FloatingIPPool.
+type FloatingIPPoolStatus struct {
+	// Nodes is the number of nodes selected by the pool.
+	Nodes int `json:"nodes"`
+
+	// Allocated is the number of FIPs assigned to nodes of the pool.
+	Allocated int `json:"allocated"`
+
+	// Free is the number of unassigned FIPs. Only set if the pool has an explicit list of FIPs.
+	Free *int `json:"free,omitempty"`
+
+	// LastUpdateTime is the time the status was last updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// FloatingIPPoolList is a list of FloatingIPPools.
+type FloatingIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []FloatingIPPool `json:"items"`
+}
@@ -0,0 +1,44 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+// Package v1alpha1 contains the custom resources of the kube-fip-controller.
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GroupName of the custom resources.
+	GroupName = "kube-fip-controller.ccloud.sap.com"
+
+	// Version of the custom resources.
+	Version = "v1alpha1"
+)
+
+var (
+	// SchemeGroupVersion is the group version of the custom resources.
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
+
+	// FloatingIPPoolResource is the resource of the FloatingIPPool.
+	FloatingIPPoolResource = SchemeGroupVersion.WithResource("floatingippools")
+
+	// FloatingIPClaimResource is the resource of the FloatingIPClaim.
+	FloatingIPClaimResource = SchemeGroupVersion.WithResource("floatingipclaims")
+)
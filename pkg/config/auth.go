@@ -20,22 +20,58 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"os"
 	"strings"
 
 	"github.com/goccy/go-yaml"
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/pkg/errors"
 )
 
+const (
+	// AuthTypePassword authenticates with username and password. This is the default.
+	AuthTypePassword = "password"
+
+	// AuthTypeApplicationCredential authenticates with an application credential.
+	AuthTypeApplicationCredential = "v3applicationcredential"
+
+	// AuthTypeToken authenticates with a pre-issued token.
+	AuthTypeToken = "token"
+)
+
+var availabilities = map[string]gophercloud.Availability{
+	"":            gophercloud.AvailabilityPublic,
+	"public":      gophercloud.AvailabilityPublic,
+	"publicURL":   gophercloud.AvailabilityPublic,
+	"internal":    gophercloud.AvailabilityInternal,
+	"internalURL": gophercloud.AvailabilityInternal,
+	"admin":       gophercloud.AvailabilityAdmin,
+	"adminURL":    gophercloud.AvailabilityAdmin,
+}
+
 // Auth used for OpenStack authentication parameters.
 type Auth struct {
-	AuthURL           string `yaml:"auth_url"`
-	RegionName        string `yaml:"region_name"`
-	Username          string `yaml:"username"`
-	UserDomainName    string `yaml:"user_domain_name"`
-	Password          string `yaml:"password"`
-	ProjectName       string `yaml:"project_name"`
-	ProjectDomainName string `yaml:"project_domain_name"`
+	AuthType                    string `yaml:"auth_type"`
+	AuthURL                     string `yaml:"auth_url"`
+	RegionName                  string `yaml:"region_name"`
+	Interface                   string `yaml:"interface"`
+	Username                    string `yaml:"username"`
+	UserDomainName              string `yaml:"user_domain_name"`
+	Password                    string `yaml:"password"`
+	PasswordFile                string `yaml:"password_file"`
+	ProjectName                 string `yaml:"project_name"`
+	ProjectDomainName           string `yaml:"project_domain_name"`
+	ApplicationCredentialID     string `yaml:"application_credential_id"`
+	ApplicationCredentialName   string `yaml:"application_credential_name"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+	Token                       string `yaml:"token"`
+	TrustID                     string `yaml:"trust_id"`
+	CAFile                      string `yaml:"ca_file"`
+	CertFile                    string `yaml:"cert_file"`
+	KeyFile                     string `yaml:"key_file"`
+	Insecure                    bool   `yaml:"insecure"`
 }
 
 // ReadAuthConfig reads a given configuration file and returns the ViceConfig object and if applicable an error.
@@ -57,25 +93,109 @@ func ReadAuthConfig(filePath string) (*Auth, error) {
 	return &tmp.Auth, err
 }
 
+// EndpointOpts returns the options for selecting the endpoints of the service clients from the catalog.
+func (a *Auth) EndpointOpts() gophercloud.EndpointOpts {
+	return gophercloud.EndpointOpts{
+		Region:       a.RegionName,
+		Availability: availabilities[a.Interface],
+	}
+}
+
+// TLSConfig returns the TLS configuration for the OpenStack APIs or nil if the defaults should be used.
+func (a *Auth) TLSConfig() (*tls.Config, error) {
+	if a.CAFile == "" && a.CertFile == "" && !a.Insecure {
+		return nil, nil
+	}
+
+	//nolint:gosec
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: a.Insecure,
+	}
+
+	if a.CAFile != "" {
+		caPEM, err := os.ReadFile(a.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read ca_file")
+		}
+		caPool, err := x509.SystemCertPool()
+		if err != nil {
+			caPool = x509.NewCertPool()
+		}
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.Errorf("no certificates found in ca_file %s", a.CAFile)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	if a.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(a.CertFile, a.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
 func (a *Auth) verify() error {
+	if a.AuthType == "" {
+		a.AuthType = AuthTypePassword
+	}
+
 	errs := make([]string, 0)
 	if a.AuthURL == "" {
 		errs = append(errs, "OS_AUTH_URL")
 	}
+	if a.RegionName == "" {
+		errs = append(errs, "OS_REGION_NAME")
+	}
+
+	if _, ok := availabilities[a.Interface]; !ok {
+		return errors.Errorf("unsupported interface %s", a.Interface)
+	}
+
+	// A client certificate requires both the certificate and the key.
+	if a.CertFile != "" && a.KeyFile == "" {
+		errs = append(errs, "key_file")
+	}
+	if a.KeyFile != "" && a.CertFile == "" {
+		errs = append(errs, "cert_file")
+	}
+
+	switch a.AuthType {
+	case AuthTypePassword:
+		errs = append(errs, a.verifyPassword()...)
+	case AuthTypeApplicationCredential:
+		errs = append(errs, a.verifyApplicationCredential()...)
+	case AuthTypeToken:
+		errs = append(errs, a.verifyToken()...)
+	default:
+		return errors.Errorf("unsupported auth_type %s", a.AuthType)
+	}
+
+	if len(errs) > 0 {
+		return errors.New("missing " + strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (a *Auth) verifyPassword() []string {
+	errs := make([]string, 0)
 	if a.Username == "" {
 		errs = append(errs, "OS_USERNAME")
 	}
 	if a.UserDomainName == "" {
 		errs = append(errs, "OS_USER_DOMAIN_NAME")
 	}
-	if a.RegionName == "" {
-		errs = append(errs, "OS_REGION_NAME")
-	}
-	if a.ProjectName == "" {
-		errs = append(errs, "OS_PROJECT_NAME")
-	}
-	if a.ProjectDomainName == "" {
-		errs = append(errs, "OS_PROJECT_DOMAIN_NAME")
+
+	// Allow providing the password via a file, e.g. a mounted secret.
+	if a.Password == "" && a.PasswordFile != "" {
+		p, err := os.ReadFile(a.PasswordFile)
+		if err != nil {
+			errs = append(errs, "readable password_file")
+		} else {
+			a.Password = strings.TrimSpace(string(p))
+		}
 	}
 
 	// Allow providing OS_PASSWORD via environment.
@@ -88,8 +208,66 @@ func (a *Auth) verify() error {
 		}
 	}
 
-	if len(errs) > 0 {
-		return errors.New("missing " + strings.Join(errs, ", "))
+	// A trust replaces the project scope.
+	if a.TrustID == "" {
+		errs = append(errs, a.verifyProjectScope()...)
 	}
-	return nil
+	return errs
+}
+
+func (a *Auth) verifyApplicationCredential() []string {
+	errs := make([]string, 0)
+	// An application credential is either identified by its ID or by its name and the user.
+	if a.ApplicationCredentialID == "" {
+		if a.ApplicationCredentialName == "" {
+			errs = append(errs, "OS_APPLICATION_CREDENTIAL_ID or OS_APPLICATION_CREDENTIAL_NAME")
+		}
+		if a.Username == "" {
+			errs = append(errs, "OS_USERNAME")
+		}
+		if a.UserDomainName == "" {
+			errs = append(errs, "OS_USER_DOMAIN_NAME")
+		}
+	}
+
+	// Allow providing OS_APPLICATION_CREDENTIAL_SECRET via environment.
+	if a.ApplicationCredentialSecret == "" {
+		s := os.Getenv("OS_APPLICATION_CREDENTIAL_SECRET")
+		if s != "" {
+			a.ApplicationCredentialSecret = s
+		} else {
+			errs = append(errs, "OS_APPLICATION_CREDENTIAL_SECRET")
+		}
+	}
+	return errs
+}
+
+func (a *Auth) verifyToken() []string {
+	errs := make([]string, 0)
+
+	// Allow providing OS_TOKEN via environment.
+	if a.Token == "" {
+		t := os.Getenv("OS_TOKEN")
+		if t != "" {
+			a.Token = t
+		} else {
+			errs = append(errs, "OS_TOKEN")
+		}
+	}
+
+	if a.TrustID == "" {
+		errs = append(errs, a.verifyProjectScope()...)
+	}
+	return errs
+}
+
+func (a *Auth) verifyProjectScope() []string {
+	errs := make([]string, 0)
+	if a.ProjectName == "" {
+		errs = append(errs, "OS_PROJECT_NAME")
+	}
+	if a.ProjectDomainName == "" {
+		errs = append(errs, "OS_PROJECT_DOMAIN_NAME")
+	}
+	return errs
 }
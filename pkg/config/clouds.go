@@ -0,0 +1,81 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package config
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/config/clouds"
+	"github.com/pkg/errors"
+)
+
+// Cloud used for OpenStack authentication parameters read from a clouds.yaml.
+type Cloud struct {
+	AuthOptions  gophercloud.AuthOptions
+	EndpointOpts gophercloud.EndpointOpts
+	TLSConfig    *tls.Config
+}
+
+// ReadCloudConfig reads the given cloud from the clouds.yaml and merges the secure.yaml next to it.
+// If no file path is given, the standard locations are searched.
+func ReadCloudConfig(cloudName, filePath string) (*Cloud, error) {
+	parseOpts := []clouds.ParseOption{clouds.WithCloudName(cloudName)}
+	if filePath != "" {
+		parseOpts = append(parseOpts, clouds.WithLocations(filePath))
+	}
+
+	authOpts, endpointOpts, tlsConfig, err := clouds.Parse(parseOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read clouds.yaml")
+	}
+
+	if authOpts.IdentityEndpoint == "" {
+		return nil, errors.Errorf("missing auth_url for cloud %s", cloudName)
+	}
+
+	// A token cannot be used to re-authenticate once it expired.
+	authOpts.AllowReauth = authOpts.TokenID == ""
+
+	return &Cloud{
+		AuthOptions:  authOpts,
+		EndpointOpts: endpointOpts,
+		TLSConfig:    tlsConfig,
+	}, nil
+}
+
+// GetCloudsFilePaths returns the paths of the clouds.yaml and secure.yaml files that might be used.
+func GetCloudsFilePaths(filePath string) []string {
+	cloudsPaths := []string{filePath}
+	if filePath == "" {
+		cloudsPaths = []string{"clouds.yaml", filepath.Join("/etc", "openstack", "clouds.yaml")}
+		if userConfig, err := os.UserConfigDir(); err == nil {
+			cloudsPaths = append(cloudsPaths, filepath.Join(userConfig, "openstack", "clouds.yaml"))
+		}
+	}
+
+	result := make([]string, 0, 2*len(cloudsPaths))
+	for _, p := range cloudsPaths {
+		result = append(result, p, filepath.Join(filepath.Dir(p), "secure.yaml"))
+	}
+	return result
+}
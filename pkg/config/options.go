@@ -22,18 +22,92 @@ package config
 import (
 	"net"
 	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FIPDeletionPolicyKeep leaves the floating IP of a deleted node untouched.
+	FIPDeletionPolicyKeep = "keep"
+
+	// FIPDeletionPolicyDisassociate disassociates the floating IP of a deleted node but keeps it allocated.
+	FIPDeletionPolicyDisassociate = "disassociate"
+
+	// FIPDeletionPolicyDelete releases the floating IP of a deleted node if it was allocated by the controller.
+	FIPDeletionPolicyDelete = "delete"
 )
 
 // Options for the controller.
 type Options struct {
 	*Auth
-	ConfigPath             string
-	KubeConfig             string
-	Threadiness            int
-	IsDebug                bool
-	RecheckInterval        time.Duration
-	MetricHost             net.IP
-	MetricPort             int
-	DefaultFloatingNetwork string
-	DefaultFloatingSubnet  string
+	*Cloud
+	ConfigPath               string
+	OSCloud                  string
+	OSClientConfigFile       string
+	ConfigReloadInterval     time.Duration
+	LivenessTimeout          time.Duration
+	KubeConfig               string
+	Threadiness              int
+	MaxRetries               int
+	RetryBaseDelay           time.Duration
+	RetryMaxDelay            time.Duration
+	SyncTimeout              time.Duration
+	ShutdownTimeout          time.Duration
+	OpenStackTimeout         time.Duration
+	KubernetesTimeout        time.Duration
+	IsDebug                  bool
+	RecheckInterval          time.Duration
+	MetricHost               net.IP
+	MetricPort               int
+	DefaultFloatingNetwork   string
+	DefaultFloatingSubnet    string
+	FIPDeletionPolicy        string
+	EnableFinalizer          bool
+	GCInterval               time.Duration
+	GCGracePeriod            time.Duration
+	GCDryRun                 bool
+	ClusterName              string
+	ProjectIDs               []string
+	DryRun                   bool
+	NetworkCacheTTL          time.Duration
+	ServerCacheTTL           time.Duration
+	NegativeCacheTTL         time.Duration
+	BulkReconciliation       bool
+	ComputeRateLimit         RateLimit
+	NetworkRateLimit         RateLimit
+	EnableFloatingIPPools    bool
+	EnableFloatingIPClaims   bool
+	LeaderElect              bool
+	LeaderElectLeaseName     string
+	LeaderElectNamespace     string
+	LeaderElectLeaseDuration time.Duration
+	LeaderElectRenewDeadline time.Duration
+	LeaderElectRetryPeriod   time.Duration
+}
+
+// Validate checks for options that are only valid in combination.
+func (o Options) Validate() error {
+	if o.GCInterval > 0 && (o.ClusterName == "" || len(o.ProjectIDs) == 0) {
+		return errors.New("--gc-interval requires --cluster-name and --project-id")
+	}
+	if o.RetryBaseDelay <= 0 {
+		return errors.New("--retry-base-delay must be greater than 0")
+	}
+	if o.MaxRetries < 0 {
+		return errors.New("--max-retries must not be negative")
+	}
+	if o.BulkReconciliation && len(o.ProjectIDs) == 0 {
+		return errors.New("--bulk-reconciliation requires --project-id")
+	}
+	return nil
+}
+
+// RateLimit limits the requests to an OpenStack service.
+type RateLimit struct {
+	// QPS is the number of requests per second. 0 disables the limit.
+	QPS float64
+	// Burst is the number of requests, which may exceed the QPS.
+	Burst int
+	// MaxInFlight is the maximum number of concurrent requests. 0 disables the limit.
+	MaxInFlight int
 }
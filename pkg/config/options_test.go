@@ -0,0 +1,52 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	valid := Options{RetryBaseDelay: 30 * time.Second, MaxRetries: 5}
+
+	tests := map[string]struct {
+		modify  func(o *Options)
+		wantErr bool
+	}{
+		"defaults":                            {modify: func(*Options) {}},
+		"no retries":                          {modify: func(o *Options) { o.MaxRetries = 0 }},
+		"negative retries":                    {modify: func(o *Options) { o.MaxRetries = -1 }, wantErr: true},
+		"zero retry delay":                    {modify: func(o *Options) { o.RetryBaseDelay = 0 }, wantErr: true},
+		"gc without cluster name":             {modify: func(o *Options) { o.GCInterval = time.Hour; o.ProjectIDs = []string{"p"} }, wantErr: true},
+		"gc with cluster name and project":    {modify: func(o *Options) { o.GCInterval = time.Hour; o.ClusterName = "c"; o.ProjectIDs = []string{"p"} }},
+		"bulk reconciliation without project": {modify: func(o *Options) { o.BulkReconciliation = true }, wantErr: true},
+		"bulk reconciliation with project":    {modify: func(o *Options) { o.BulkReconciliation = true; o.ProjectIDs = []string{"p"} }},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := valid
+			tc.modify(&opts)
+			if err := opts.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("expected error: %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
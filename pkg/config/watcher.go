@@ -0,0 +1,64 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"time"
+)
+
+// WatchFiles calls onChange whenever the content of one of the files changed.
+// Files are polled rather than watched for events as secrets mounted in Kubernetes are replaced via symlinks.
+// If onChange fails, it is called again with the next poll until it succeeds.
+func WatchFiles(filePaths []string, interval time.Duration, stopCh <-chan struct{}, onChange func() error) {
+	checksums := getChecksums(filePaths)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			newChecksums := getChecksums(filePaths)
+			if newChecksums != checksums && onChange() == nil {
+				checksums = newChecksums
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// getChecksums returns a checksum over the content of all files. Files that cannot be read are treated as empty.
+func getChecksums(filePaths []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, filePath := range filePaths {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			content = nil
+		}
+		h.Write([]byte(filePath))
+		h.Write(content)
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,68 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchFilesRetriesFailedChange(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(filePath, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WatchFiles([]string{filePath}, 10*time.Millisecond, stopCh, func() error {
+			// The first reload fails, e.g. because Keystone is not reachable.
+			if calls.Add(1) == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	}()
+
+	// Give the watcher time to compute the initial checksum.
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filePath, []byte("new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Once it succeeded, the change is not reported again.
+	time.Sleep(50 * time.Millisecond)
+	close(stopCh)
+	<-done
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected the failed change to be retried once, got %d calls", n)
+	}
+}
@@ -0,0 +1,183 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/kube-fip-controller/pkg/frameworks"
+)
+
+// Kinds of drift between the nodes and the FIPs in OpenStack.
+const (
+	DriftEnabledNodeWithoutFIP  = "EnabledNodeWithoutFIP"
+	DriftMissingFIP             = "MissingFIP"
+	DriftServerNotFound         = "ServerNotFound"
+	DriftFIPNotAssociated       = "FIPNotAssociated"
+	DriftFIPAssociatedElsewhere = "FIPAssociatedElsewhere"
+	DriftOrphanedFIP            = "OrphanedFIP"
+)
+
+// Drift describes a mismatch between a node and the FIPs in OpenStack.
+type Drift struct {
+	Kind       string `json:"kind"`
+	Node       string `json:"node,omitempty"`
+	FloatingIP string `json:"floatingIP,omitempty"`
+	FIPID      string `json:"fipID,omitempty"`
+	ServerID   string `json:"serverID,omitempty"`
+	Message    string `json:"message"`
+}
+
+// AuditReport is the result of an audit.
+type AuditReport struct {
+	Drifts []Drift `json:"drifts"`
+}
+
+// HasDrift checks whether the audit found any drift.
+func (r *AuditReport) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+// WriteTable writes the report as table.
+func (r *AuditReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNODE\tFIP\tFIP ID\tSERVER ID\tMESSAGE") //nolint:errcheck
+	for _, d := range r.Drifts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", d.Kind, d.Node, d.FloatingIP, d.FIPID, d.ServerID, d.Message) //nolint:errcheck
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report as JSON.
+func (r *AuditReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r *AuditReport) add(kind string, node *corev1.Node, fip *neutronfip.FloatingIP, serverID, msgFmt string, args ...interface{}) {
+	d := Drift{
+		Kind:     kind,
+		ServerID: serverID,
+		Message:  fmt.Sprintf(msgFmt, args...),
+	}
+	if node != nil {
+		d.Node = node.GetName()
+	}
+	if fip != nil {
+		d.FloatingIP = fip.FloatingIP
+		d.FIPID = fip.ID
+	}
+	r.Drifts = append(r.Drifts, d)
+}
+
+// Audit compares the enabled nodes with the FIPs in OpenStack and reports mismatches without changing anything.
+func (c *Controller) Audit(ctx context.Context) (*AuditReport, error) {
+	c.k8sFramework.Run(ctx.Done())
+	if !c.k8sFramework.WaitForCacheToSync(ctx.Done()) {
+		return nil, errors.New("timed out while waiting for informer caches to sync")
+	}
+
+	fips, err := c.osFramework.ListFloatingIPs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list FIPs: %w", err)
+	}
+	fipsByAddress := make(map[string]*neutronfip.FloatingIP, len(fips))
+	for i := range fips {
+		fipsByAddress[fips[i].FloatingIP] = &fips[i]
+	}
+
+	nodes := make([]*corev1.Node, 0)
+	for _, obj := range c.k8sFramework.GetNodeInformerStore().List() {
+		if node, ok := obj.(*corev1.Node); ok && isEnabled(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].GetName() < nodes[j].GetName() })
+
+	report := &AuditReport{Drifts: make([]Drift, 0)}
+	for _, node := range nodes {
+		if err := c.auditNode(ctx, node, fipsByAddress, report); err != nil {
+			return nil, fmt.Errorf("failed to audit node %s: %w", node.GetName(), err)
+		}
+	}
+
+	referencedFIPs, reusedNodepools := c.getReferencedFIPsAndReusedNodepools()
+	if err := c.addFloatingIPPoolFIPs(referencedFIPs); err != nil {
+		return nil, fmt.Errorf("failed to list floating ip pools: %w", err)
+	}
+	for i := range fips {
+		fip := &fips[i]
+		// FIPs within the grace period might have been allocated for a node that is not labeled yet, like in the garbage collection.
+		if time.Since(getLastUpdate(fip)) < c.opts.GCGracePeriod {
+			continue
+		}
+		if frameworks.IsCreatedByController(fip) && frameworks.HasClusterTag(fip, c.opts.ClusterName) && isOrphanedFIP(fip, referencedFIPs, reusedNodepools) {
+			report.add(DriftOrphanedFIP, nil, fip, "", "FIP allocated by the controller is not referenced by any node")
+		}
+	}
+	return report, nil
+}
+
+func (c *Controller) auditNode(ctx context.Context, node *corev1.Node, fipsByAddress map[string]*neutronfip.FloatingIP, report *AuditReport) error {
+	floatingIP, ok := getLabelValue(node, labelExternalIP)
+	if !ok || floatingIP == "" {
+		report.add(DriftEnabledNodeWithoutFIP, node, nil, "", "enabled node has no %s label", labelExternalIP)
+		return nil
+	}
+
+	fip, ok := fipsByAddress[floatingIP]
+	if !ok {
+		report.add(DriftMissingFIP, node, nil, "", "label %s points at FIP %s which does not exist", labelExternalIP, floatingIP)
+		return nil
+	}
+
+	server, err := c.getServer(ctx, node)
+	if err != nil {
+		if frameworks.IsServerNotFound(err) {
+			report.add(DriftServerNotFound, node, fip, "", "%s", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	associatedServerID, err := c.osFramework.GetAssociatedServerID(ctx, fip)
+	if err != nil {
+		return err
+	}
+	switch associatedServerID {
+	case server.ID:
+	case "":
+		report.add(DriftFIPNotAssociated, node, fip, server.ID, "FIP is not associated with any server")
+	default:
+		report.add(DriftFIPAssociatedElsewhere, node, fip, associatedServerID, "FIP is associated with server %s instead of %s", associatedServerID, server.ID)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sapcc/kube-fip-controller/pkg/apis/v1alpha1"
+)
+
+const (
+	claimReasonSynced     = "Synced"
+	claimReasonSyncFailed = "SyncFailed"
+)
+
+// updateFloatingIPClaim reflects the result of the node's sync in its FloatingIPClaim.
+func (c *Controller) updateFloatingIPClaim(ctx context.Context, node *corev1.Node, result *syncResult, syncErr error) error {
+	claim, err := c.k8sFramework.GetOrCreateFloatingIPClaim(ctx, node)
+	if err != nil {
+		return err
+	}
+
+	newClaim := claim.DeepCopy()
+	status := &newClaim.Status
+	if result.floatingNetworkID != "" {
+		status.FloatingNetworkID = result.floatingNetworkID
+	}
+	if result.floatingSubnetID != "" {
+		status.FloatingSubnetID = result.floatingSubnetID
+	}
+	if result.server != nil {
+		status.ServerID = result.server.ID
+	}
+	if result.fip != nil {
+		status.FloatingIPID = result.fip.ID
+		status.FloatingIP = result.fip.FloatingIP
+		status.PortID = result.fip.PortID
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.FloatingIPClaimConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             claimReasonSynced,
+		Message:            "FIP is associated with the server",
+		ObservedGeneration: newClaim.GetGeneration(),
+	}
+	if syncErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = claimReasonSyncFailed
+		condition.Message = syncErr.Error()
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	// The claim is only updated if its status changed, so unchanged nodes do not cause requests to the API server.
+	if apiequality.Semantic.DeepEqual(claim.Status, newClaim.Status) {
+		return nil
+	}
+	status.LastSyncTime = metav1.Now()
+	return c.k8sFramework.UpdateFloatingIPClaimStatus(ctx, newClaim)
+}
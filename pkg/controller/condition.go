@@ -0,0 +1,57 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// nodeConditionFloatingIPReady indicates whether the FIP of the node is associated with its server.
+	nodeConditionFloatingIPReady corev1.NodeConditionType = "FloatingIPReady"
+
+	conditionReasonSyncFailed = "SyncFailed"
+)
+
+// updateNodeCondition reflects the result of the node's sync in the FloatingIPReady condition.
+func (c *Controller) updateNodeCondition(ctx context.Context, node *corev1.Node, result *syncResult, syncErr error) error {
+	condition := corev1.NodeCondition{
+		Type:   nodeConditionFloatingIPReady,
+		Status: corev1.ConditionTrue,
+		Reason: eventReasonFIPAssociated,
+	}
+
+	switch {
+	case syncErr != nil:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = conditionReasonSyncFailed
+		if reason, ok := getReasonForError(syncErr); ok {
+			condition.Reason = reason
+		}
+		condition.Message = syncErr.Error()
+	case result.fip != nil && result.server != nil:
+		condition.Message = fmt.Sprintf("FIP %s is associated with server %s", result.fip.FloatingIP, result.server.ID)
+	}
+
+	return c.k8sFramework.SetNodeCondition(ctx, node, condition)
+}
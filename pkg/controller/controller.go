@@ -22,18 +22,26 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/sapcc/kube-fip-controller/pkg/apis/v1alpha1"
 	"github.com/sapcc/kube-fip-controller/pkg/config"
 	"github.com/sapcc/kube-fip-controller/pkg/frameworks"
 	"github.com/sapcc/kube-fip-controller/pkg/metrics"
@@ -57,6 +65,15 @@ const (
 
 	// labelReuseFIPs indicates if FIPs should be re-used for a certain nodepool
 	labelReuseFIPs = "kube-fip-controller.ccloud.sap.com/reuse-fips"
+
+	// finalizerFIPCleanup ensures the FIP of a node is released before the node is deleted.
+	finalizerFIPCleanup = "kube-fip-controller.ccloud.sap.com/fip-cleanup"
+
+	// queueName is used for the workqueue metrics.
+	queueName = "nodes"
+
+	reconcileResultSuccess = "success"
+	reconcileResultError   = "error"
 )
 
 // Controller ...
@@ -65,20 +82,33 @@ type Controller struct {
 	logger       log.Logger
 	queue        workqueue.TypedRateLimitingInterface[interface{}]
 	k8sFramework *frameworks.K8sFramework
-	osFramework  *frameworks.OSFramework
-}
+	osFramework  frameworks.OpenStack
 
-var (
-	ctx = context.Background()
-)
+	// deletedNodes holds the last known state of deleted nodes until their FIP was released.
+	// finalizedNodes holds nodes whose FIP was already released by the finalizer.
+	deletedNodesMtx sync.Mutex
+	deletedNodes    map[string]*corev1.Node
+	finalizedNodes  map[types.UID]struct{}
+
+	// poolReservations maps FIPs allocated from a pool to the node until the node carries the label.
+	poolReservationsMtx sync.Mutex
+	poolReservations    map[string]string
+
+	// workersStarted and lastWorkerActivity are used for the liveness check.
+	workersStarted     atomic.Bool
+	lastWorkerActivity atomic.Int64
+}
 
 // New returns a new Controller or an error.
-func New(opts config.Options, logger log.Logger) (*Controller, error) {
-	authConfig, err := config.ReadAuthConfig(opts.ConfigPath)
+func New(ctx context.Context, opts config.Options, logger log.Logger) (*Controller, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts, err := loadAuthConfig(opts)
 	if err != nil {
 		return nil, err
 	}
-	opts.Auth = authConfig
 
 	k8sFramework, err := frameworks.NewK8sFramework(opts, logger)
 	if err != nil {
@@ -90,45 +120,116 @@ func New(opts config.Options, logger log.Logger) (*Controller, error) {
 		return nil, err
 	}
 
+	return newController(opts, k8sFramework, osFramework, logger), nil
+}
+
+func newController(opts config.Options, k8sFramework *frameworks.K8sFramework, osFramework frameworks.OpenStack, logger log.Logger) *Controller {
 	c := &Controller{
-		opts:         opts,
-		logger:       log.With(logger, "component", "controller"),
-		queue:        workqueue.NewTypedRateLimitingQueue(workqueue.NewTypedItemExponentialFailureRateLimiter[interface{}](30*time.Second, 600*time.Second)),
-		k8sFramework: k8sFramework,
-		osFramework:  osFramework,
+		opts:   opts,
+		logger: log.With(logger, "component", "controller"),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[interface{}](opts.RetryBaseDelay, opts.RetryMaxDelay),
+			workqueue.TypedRateLimitingQueueConfig[interface{}]{Name: queueName},
+		),
+		k8sFramework:     k8sFramework,
+		osFramework:      osFramework,
+		deletedNodes:     make(map[string]*corev1.Node),
+		finalizedNodes:   make(map[types.UID]struct{}),
+		poolReservations: make(map[string]string),
 	}
 
 	c.k8sFramework.AddEventHandlerFuncsToNodeInformer(
 		c.enqueueItem,
-		c.enqueueItem,
+		c.handleNodeDelete,
 		func(oldObj, newObj interface{}) {
 			o := oldObj.(*corev1.Node) //nolint:errcheck
 			n := newObj.(*corev1.Node) //nolint:errcheck
-			if !reflect.DeepEqual(o.GetAnnotations(), n.GetAnnotations()) || !reflect.DeepEqual(o.GetLabels(), n.GetLabels()) {
+			if !reflect.DeepEqual(o.GetAnnotations(), n.GetAnnotations()) || !reflect.DeepEqual(o.GetLabels(), n.GetLabels()) ||
+				!reflect.DeepEqual(o.GetDeletionTimestamp(), n.GetDeletionTimestamp()) {
 				c.enqueueItem(newObj)
 			}
 		},
 	)
-	return c, nil
+	c.k8sFramework.AddEventHandlerFuncsToFloatingIPPoolInformer(func(_ interface{}) {
+		c.enqueueAllItems()
+	})
+	return c
 }
 
-// Run starts the Controller.
-func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) {
+// Run starts the Controller and blocks until the context is cancelled and the workers stopped.
+// On shutdown, in-flight syncs are given the shutdown timeout to finish before they are cancelled.
+func (c *Controller) Run(ctx context.Context, threadiness int) {
 	defer utilruntime.HandleCrash()
 	defer c.queue.ShutDown()
 
 	_ = level.Info(c.logger).Log("msg", "starting controller") //nolint:errcheck
 
-	c.k8sFramework.Run(stopCh)
+	// Events are only recorded by the controller, so the audit does not start the recording.
+	c.k8sFramework.StartEventRecording()
+	c.k8sFramework.Run(ctx.Done())
 	_ = level.Info(c.logger).Log("msg", "waiting for caches to sync") //nolint:errcheck
 
-	if !c.k8sFramework.WaitForCacheToSync(stopCh) {
+	if !c.k8sFramework.WaitForCacheToSync(ctx.Done()) {
 		utilruntime.HandleError(errors.New("timed out while waiting for informer caches to sync"))
 		return
 	}
 
+	if c.opts.ConfigReloadInterval > 0 {
+		go config.WatchFiles(c.getConfigFilePaths(), c.opts.ConfigReloadInterval, ctx.Done(), func() error { return c.reloadConfig(ctx) })
+	}
+
+	// Syncs must not be interrupted as soon as the shutdown begins, so they use a context that is cancelled separately.
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	if c.opts.LeaderElect {
+		releaseLeadership := c.runWithLeaderElection(ctx, workCtx, threadiness)
+		defer releaseLeadership()
+	} else {
+		if identity, err := os.Hostname(); err == nil {
+			metrics.MetricLeader.WithLabelValues(identity).Set(1)
+		}
+		c.startWorkers(ctx, workCtx, threadiness)
+	}
+
+	<-ctx.Done()
+	_ = level.Info(c.logger).Log("msg", "stopping controller", "timeout", c.opts.ShutdownTimeout.String()) //nolint:errcheck
+	c.drainQueue(cancelWork)
+	_ = level.Info(c.logger).Log("msg", "stopped controller") //nolint:errcheck
+}
+
+// drainQueue shuts down the queue and waits for in-flight syncs to finish.
+// Syncs still running after the shutdown timeout are cancelled.
+func (c *Controller) drainQueue(cancelWork context.CancelFunc) {
+	drained := make(chan struct{})
+	go func() {
+		c.queue.ShutDownWithDrain()
+		close(drained)
+	}()
+
+	timer := time.NewTimer(c.opts.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-drained:
+	case <-timer.C:
+		_ = level.Error(c.logger).Log("msg", "in-flight syncs did not finish within the shutdown timeout. cancelling them", "timeout", c.opts.ShutdownTimeout.String()) //nolint:errcheck
+		cancelWork()
+		// Stop waiting for syncs that do not return although they were cancelled.
+		c.queue.ShutDown()
+		<-drained
+	}
+}
+
+// startWorkers starts the workers, the periodic recheck and garbage collection.
+// They stop once ctx is cancelled, while syncs use workCtx, so that in-flight syncs can finish.
+func (c *Controller) startWorkers(ctx, workCtx context.Context, threadiness int) {
+	c.refreshSnapshot(ctx)
+	// The metric is computed right away, as nodes are most likely missing FIPs after a start or a change of the leader.
+	c.updateEnabledNodesWithoutFIPMetric()
+	c.lastWorkerActivity.Store(time.Now().UnixNano())
+	c.workersStarted.Store(true)
 	for range threadiness {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.Until(func() { c.runWorker(ctx, workCtx) }, time.Second, ctx.Done())
 	}
 
 	ticker := time.NewTicker(c.opts.RecheckInterval)
@@ -136,112 +237,242 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) {
 		for {
 			select {
 			case <-ticker.C:
+				c.refreshSnapshot(ctx)
 				c.enqueueAllItems()
+				c.updateEnabledNodesWithoutFIPMetric()
+				if c.opts.EnableFloatingIPPools {
+					c.updateFloatingIPPoolStatuses(ctx)
+				}
 				_ = level.Info(c.logger).Log("msg", "completed another cycle", "interval", c.opts.RecheckInterval.String()) //nolint:errcheck
-			case <-stopCh:
+			case <-ctx.Done():
 				ticker.Stop()
 				return
 			}
 		}
 	}()
 
-	<-stopCh
-	_ = level.Info(c.logger).Log("msg", "stopping controller") //nolint:errcheck
+	if c.opts.GCInterval > 0 {
+		go wait.UntilWithContext(ctx, c.collectGarbage, c.opts.GCInterval)
+	}
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextItem() {
+func (c *Controller) runWorker(ctx, workCtx context.Context) {
+	for c.processNextItem(ctx, workCtx) {
 	}
 }
 
-func (c *Controller) processNextItem() bool {
+func (c *Controller) processNextItem(ctx, workCtx context.Context) bool {
 	key, quit := c.queue.Get()
 	if quit {
 		return false
 	}
-	defer c.queue.Done(key)
+	c.lastWorkerActivity.Store(time.Now().UnixNano())
+	defer func() {
+		c.queue.Done(key)
+		c.lastWorkerActivity.Store(time.Now().UnixNano())
+	}()
+
+	// Items still queued on shutdown are dropped. All nodes are synced again after the restart.
+	if ctx.Err() != nil {
+		return false
+	}
 
-	err := c.syncHandler(key.(string)) //nolint:errcheck
+	syncCtx, cancel := withTimeout(workCtx, c.opts.SyncTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.syncHandler(syncCtx, key.(string)) //nolint:errcheck
+	result := reconcileResultSuccess
+	if err != nil {
+		result = reconcileResultError
+	}
+	metrics.MetricReconcileDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
 	c.handleError(err, key)
 	return true
 }
 
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	node, exists, err := c.k8sFramework.GetNodeFromIndexerByKey(key)
 	if err != nil {
 		_ = level.Error(c.logger).Log("msg", "failed to get object from store", "err", err) //nolint:errcheck
 		return err
 	}
 
+	if deletedNode, ok := c.getDeletedNode(key); ok {
+		if err := c.cleanupDeletedNode(ctx, deletedNode, node); err != nil {
+			return err
+		}
+		c.forgetDeletedNode(key)
+	}
+
 	if !exists {
 		_ = level.Debug(c.logger).Log("msg", "node does not exist anymore", "key", key) //nolint:errcheck
+		metrics.DeleteNodeFIPMetrics(key)
 		return nil
 	}
 
+	if node.GetDeletionTimestamp() != nil {
+		metrics.DeleteNodeFIPMetrics(key)
+		return c.finalizeNode(ctx, node)
+	}
+
 	// Ignore the node if enable label is not set.
-	val, ok := getLabelValue(node, labelKubeFIPControllerEnabled)
-	if !ok || val != "true" {
+	if !isEnabled(node) {
 		_ = level.Debug(c.logger).Log("msg", "ignoring node as label not set", "node", node.GetName(), "label", labelKubeFIPControllerEnabled) //nolint:errcheck
+		metrics.DeleteNodeFIPMetrics(key)
+		// Do not block the deletion of nodes that are no longer handled by the controller.
+		if hasFinalizer(node, finalizerFIPCleanup) {
+			return c.k8sFramework.RemoveFinalizerFromNode(ctx, node, finalizerFIPCleanup)
+		}
 		return nil
 	}
 
-	floatingNetworkName := c.opts.DefaultFloatingNetwork
+	// Add the finalizer before allocating a FIP, so the FIP is released even if the controller misses the deletion.
+	if c.opts.EnableFinalizer && !hasFinalizer(node, finalizerFIPCleanup) {
+		if err := c.k8sFramework.AddFinalizerToNode(ctx, node, finalizerFIPCleanup); err != nil {
+			return err
+		}
+	}
+
+	result, err := c.syncNode(ctx, node)
+	if err != nil {
+		c.recordErrorEvent(node, err)
+	}
+	c.updateNodeFIPMetrics(node, result, err)
+	if condErr := c.updateNodeCondition(ctx, node, result, err); condErr != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to update node condition", "node", node.GetName(), "err", condErr) //nolint:errcheck
+	}
+	if c.opts.EnableFloatingIPClaims {
+		if claimErr := c.updateFloatingIPClaim(ctx, node, result, err); claimErr != nil {
+			_ = level.Error(c.logger).Log("msg", "failed to update floating ip claim", "node", node.GetName(), "err", claimErr) //nolint:errcheck
+		}
+	}
+	return err
+}
+
+// syncResult holds the state of the node's FIP as far as it was determined by syncNode.
+type syncResult struct {
+	floatingNetworkName string
+	floatingNetworkID   string
+	floatingSubnetName  string
+	floatingSubnetID    string
+	nodepool            string
+	server              *servers.Server
+	fip                 *neutronfip.FloatingIP
+}
+
+// syncNode ensures an enabled node has a FIP associated with its server.
+// The result is never nil and contains what is known even if an error is returned.
+func (c *Controller) syncNode(ctx context.Context, node *corev1.Node) (*syncResult, error) {
+	result := &syncResult{}
+
+	pool, err := c.getFloatingIPPoolForNode(node)
+	if err != nil {
+		return result, err
+	}
+
+	// Labels on the node take precedence over the pool, which takes precedence over the defaults.
+	result.floatingNetworkName = c.opts.DefaultFloatingNetwork
+	if pool != nil && pool.Spec.FloatingNetworkName != "" {
+		result.floatingNetworkName = pool.Spec.FloatingNetworkName
+	}
 	if val, ok := getLabelValue(node, labelFloatingNetworkName); ok && val != "" {
-		floatingNetworkName = val
+		result.floatingNetworkName = val
 	}
 
-	floatingNetworkID, err := c.osFramework.GetNetworkIDByName(ctx, floatingNetworkName)
+	result.floatingNetworkID, err = c.osFramework.GetNetworkIDByName(ctx, result.floatingNetworkName)
 	if err != nil {
-		return err
+		return result, err
 	}
 
-	floatingSubnetName := c.opts.DefaultFloatingSubnet
+	result.floatingSubnetName = c.opts.DefaultFloatingSubnet
+	if pool != nil && pool.Spec.FloatingSubnetName != "" {
+		result.floatingSubnetName = pool.Spec.FloatingSubnetName
+	}
 	if val, ok := getLabelValue(node, labelFloatingSubnetName); ok && val != "" {
-		floatingSubnetName = val
+		result.floatingSubnetName = val
 	}
 
-	floatingSubnetID, err := c.osFramework.GetSubnetIDByName(ctx, floatingSubnetName)
+	result.floatingSubnetID, err = c.osFramework.GetSubnetIDByName(ctx, result.floatingSubnetName)
 	if err != nil {
-		return err
+		return result, err
 	}
 
 	floatingIP := ""
 	if val, ok := getLabelValue(node, labelExternalIP); ok {
 		floatingIP = val
 	}
+	if floatingIP == "" && pool != nil && len(pool.Spec.FloatingIPs) > 0 {
+		floatingIP, err = c.allocateFloatingIPFromPool(pool, node, nil)
+		if err != nil {
+			return result, err
+		}
+	}
 
-	server, err := c.getServer(ctx, node)
+	result.server, err = c.getServer(ctx, node)
 	if err != nil {
-		return err
+		return result, err
 	}
 
-	nodepool := ""
 	if val, ok := getLabelValue(node, labelNodepoolName); ok {
-		nodepool = val
+		result.nodepool = val
 	}
 
-	reuseFIPs := false
+	reuseFIPs := pool != nil && pool.Spec.ReusePolicy == v1alpha1.ReusePolicyNodepool
 	if val, ok := getLabelValue(node, labelReuseFIPs); ok {
 		reuseFIPs = (val == "true")
 	}
 
-	fip, err := c.osFramework.GetOrCreateFloatingIP(ctx, floatingIP, floatingNetworkID, floatingSubnetID, server.TenantID, nodepool, reuseFIPs)
+	// A FIP of the pool might have been associated with another server. The next free one of the pool is used instead.
+	excludedFIPs := make(map[string]struct{})
+	for {
+		err = c.ensureFIPOfNode(ctx, node, result, floatingIP, reuseFIPs)
+		if pool == nil || !slices.Contains(pool.Spec.FloatingIPs, floatingIP) || !frameworks.IsFIPAssociatedElsewhere(err) {
+			return result, err
+		}
+
+		_ = level.Info(c.logger).Log("msg", "FIP of pool is associated elsewhere. allocating another one", "node", node.GetName(), "pool", pool.GetName(), "fip", floatingIP) //nolint:errcheck
+		excludedFIPs[floatingIP] = struct{}{}
+		c.releasePoolReservation(node, floatingIP)
+		floatingIP, err = c.allocateFloatingIPFromPool(pool, node, excludedFIPs)
+		if err != nil {
+			return result, err
+		}
+	}
+}
+
+// ensureFIPOfNode gets or creates the FIP, labels the node with it and associates it with the node's server.
+func (c *Controller) ensureFIPOfNode(ctx context.Context, node *corev1.Node, result *syncResult, floatingIP string, reuseFIPs bool) error {
+	fip, created, err := c.osFramework.GetOrCreateFloatingIP(ctx, floatingIP, result.floatingNetworkID, result.floatingSubnetID, result.server.TenantID, result.nodepool, result.server.ID, reuseFIPs)
 	if err != nil {
 		return err
 	}
+	result.fip = fip
+	if created {
+		c.k8sFramework.RecordNodeEvent(node, corev1.EventTypeNormal, eventReasonFIPCreated, "Created FIP %s", fip.FloatingIP)
+	}
 
 	// Add the FIP to the node as label.
 	err = c.k8sFramework.AddLabelsToNode(
 		ctx, node,
 		map[string]string{
-			labelExternalIP: fip.FloatingIP,
+			labelExternalIP: result.fip.FloatingIP,
 		},
 	)
 	if err != nil {
 		return err
 	}
 
-	return c.osFramework.EnsureAssociatedInstanceAndFIP(ctx, server, fip)
+	fip, associated, err := c.osFramework.EnsureAssociatedInstanceAndFIP(ctx, result.server, result.fip)
+	if err != nil {
+		return err
+	}
+	result.fip = fip
+	if associated {
+		c.k8sFramework.RecordNodeEvent(node, corev1.EventTypeNormal, eventReasonFIPAssociated, "Associated FIP %s with server %s", fip.FloatingIP, result.server.ID)
+	}
+
+	return nil
 }
 
 func (c *Controller) handleError(err error, key interface{}) {
@@ -252,8 +483,16 @@ func (c *Controller) handleError(err error, key interface{}) {
 	}
 	metrics.MetricFailedOperations.Inc()
 
-	if c.queue.NumRequeues(key) < 5 {
-		_ = level.Info(c.logger).Log("msg", "error syncing key", "key", key, "err", err) //nolint:errcheck
+	// Permanent errors are reflected in the node's events and condition. The node is synced again on changes or the next recheck.
+	if frameworks.IsPermanent(err) {
+		c.queue.Forget(key)
+		_ = level.Info(c.logger).Log("msg", "not retrying permanent error", "key", key, "err", err) //nolint:errcheck
+		return
+	}
+
+	// Unclassified errors, e.g. a port of a new server that is not created yet, are retried like transient ones.
+	if c.queue.NumRequeues(key) < c.opts.MaxRetries {
+		_ = level.Info(c.logger).Log("msg", "error syncing key", "key", key, "transient", frameworks.IsTransient(err), "err", err) //nolint:errcheck
 		c.queue.AddRateLimited(key)
 		return
 	}
@@ -272,10 +511,140 @@ func (c *Controller) enqueueItem(obj interface{}) {
 	c.queue.AddRateLimited(key)
 }
 
+// refreshSnapshot lists all FIPs and ports for the next cycle if bulk reconciliation is enabled.
+// If this fails, the workers request FIPs and ports individually.
+func (c *Controller) refreshSnapshot(ctx context.Context) {
+	if !c.opts.BulkReconciliation {
+		return
+	}
+	if err := c.osFramework.RefreshSnapshot(ctx); err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to refresh snapshot. falling back to individual requests", "err", err) //nolint:errcheck
+	}
+}
+
 func (c *Controller) enqueueAllItems() {
 	for _, obj := range c.k8sFramework.GetNodeInformerStore().List() {
 		c.enqueueItem(obj)
 	}
+
+	// Retry the cleanup of deleted nodes that previously failed.
+	c.deletedNodesMtx.Lock()
+	defer c.deletedNodesMtx.Unlock()
+	for key := range c.deletedNodes {
+		c.queue.AddRateLimited(key)
+	}
+}
+
+func (c *Controller) handleNodeDelete(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("unexpected object in node delete handler: %#v", obj))
+			return
+		}
+		node, ok = tombstone.Obj.(*corev1.Node)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("tombstone contained object that is not a node: %#v", tombstone.Obj))
+			return
+		}
+	}
+
+	c.deletedNodesMtx.Lock()
+	_, finalized := c.finalizedNodes[node.GetUID()]
+	delete(c.finalizedNodes, node.GetUID())
+	if !finalized && c.opts.FIPDeletionPolicy != config.FIPDeletionPolicyKeep && isEnabled(node) {
+		if val, ok := getLabelValue(node, labelExternalIP); ok && val != "" {
+			c.deletedNodes[node.GetName()] = node
+		}
+	}
+	c.deletedNodesMtx.Unlock()
+	c.enqueueItem(node)
+}
+
+func (c *Controller) getDeletedNode(key string) (*corev1.Node, bool) {
+	c.deletedNodesMtx.Lock()
+	defer c.deletedNodesMtx.Unlock()
+	node, ok := c.deletedNodes[key]
+	return node, ok
+}
+
+func (c *Controller) forgetDeletedNode(key string) {
+	c.deletedNodesMtx.Lock()
+	defer c.deletedNodesMtx.Unlock()
+	delete(c.deletedNodes, key)
+}
+
+// cleanupDeletedNode releases the FIP of a deleted node according to the deletion policy.
+// The currentNode is the node with the same name if it was recreated in the meantime.
+func (c *Controller) cleanupDeletedNode(ctx context.Context, deletedNode, currentNode *corev1.Node) error {
+	floatingIP, _ := getLabelValue(deletedNode, labelExternalIP) //nolint:errcheck
+	if currentNode != nil && currentNode.GetUID() != deletedNode.GetUID() {
+		if val, ok := getLabelValue(currentNode, labelExternalIP); ok && val == floatingIP {
+			_ = level.Info(c.logger).Log("msg", "FIP still used by recreated node", "node", currentNode.GetName(), "fip", floatingIP) //nolint:errcheck
+			return nil
+		}
+	}
+	return c.releaseFloatingIP(ctx, deletedNode)
+}
+
+// releaseFloatingIP disassociates or deletes the FIP of the given node according to the deletion policy.
+func (c *Controller) releaseFloatingIP(ctx context.Context, node *corev1.Node) error {
+	floatingIP, ok := getLabelValue(node, labelExternalIP)
+	if !ok || floatingIP == "" || c.opts.FIPDeletionPolicy == config.FIPDeletionPolicyKeep {
+		return nil
+	}
+
+	fip, err := c.osFramework.GetFloatingIPByAddress(ctx, floatingIP)
+	if err != nil {
+		if frameworks.IsFIPNotFound(err) {
+			_ = level.Info(c.logger).Log("msg", "FIP of deleted node not found", "node", node.GetName(), "fip", floatingIP) //nolint:errcheck
+			return nil
+		}
+		return err
+	}
+
+	// Never touch a FIP that was meanwhile associated with another server, e.g. reused by another node of the nodepool.
+	if serverID, err := getServerIDFromNode(node); err == nil {
+		isAssociatedElsewhere, err := c.osFramework.IsAssociatedWithOtherServer(ctx, fip, serverID)
+		if err != nil {
+			return err
+		}
+		if isAssociatedElsewhere {
+			_ = level.Info(c.logger).Log("msg", "FIP of deleted node is associated with another server", "node", node.GetName(), "fip", floatingIP) //nolint:errcheck
+			return nil
+		}
+	}
+
+	_ = level.Info(c.logger).Log("msg", "releasing FIP of deleted node", "node", node.GetName(), "fip", floatingIP, "policy", c.opts.FIPDeletionPolicy) //nolint:errcheck
+	switch c.opts.FIPDeletionPolicy {
+	case config.FIPDeletionPolicyDisassociate:
+		return c.osFramework.DisassociateFloatingIP(ctx, fip)
+	case config.FIPDeletionPolicyDelete:
+		return c.osFramework.DeleteFloatingIP(ctx, fip)
+	default:
+		return nil
+	}
+}
+
+// finalizeNode releases the FIP of a node that is being deleted and removes the finalizer afterwards.
+func (c *Controller) finalizeNode(ctx context.Context, node *corev1.Node) error {
+	if !hasFinalizer(node, finalizerFIPCleanup) {
+		return nil
+	}
+
+	if err := c.releaseFloatingIP(ctx, node); err != nil {
+		return err
+	}
+	if err := c.k8sFramework.RemoveFinalizerFromNode(ctx, node, finalizerFIPCleanup); err != nil {
+		return err
+	}
+
+	c.deletedNodesMtx.Lock()
+	defer c.deletedNodesMtx.Unlock()
+	c.finalizedNodes[node.GetUID()] = struct{}{}
+	delete(c.deletedNodes, node.GetName())
+	return nil
 }
 
 func (c *Controller) getServer(ctx context.Context, node *corev1.Node) (*servers.Server, error) {
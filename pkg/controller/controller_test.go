@@ -0,0 +1,590 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/sapcc/kube-fip-controller/pkg/apis/v1alpha1"
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+	"github.com/sapcc/kube-fip-controller/pkg/frameworks"
+	"github.com/sapcc/kube-fip-controller/pkg/frameworks/fake"
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+const (
+	testNetwork     = "floating-network"
+	testSubnet      = "floating-subnet"
+	testProject     = "project-1"
+	testClusterName = "cluster-a"
+	testServerID    = "server-1"
+	testNodeName    = "node-1"
+)
+
+type testEnv struct {
+	controller *Controller
+	kube       *kubefake.Clientset
+	dynamic    *dynamicfake.FakeDynamicClient
+	openstack  *fake.OpenStack
+}
+
+func newTestEnv(t *testing.T, nodes ...*corev1.Node) *testEnv {
+	t.Helper()
+	return newTestEnvWithPools(t, nil, nodes...)
+}
+
+// newTestEnvWithPools enables FloatingIPPools if any are given.
+func newTestEnvWithPools(t *testing.T, pools []*v1alpha1.FloatingIPPool, nodes ...*corev1.Node) *testEnv {
+	t.Helper()
+	return newTestEnvWithOptions(t, func(*config.Options) {}, pools, nodes...)
+}
+
+// newTestEnvWithOptions allows to change the options before the frameworks are created.
+func newTestEnvWithOptions(t *testing.T, setOpts func(*config.Options), pools []*v1alpha1.FloatingIPPool, nodes ...*corev1.Node) *testEnv {
+	t.Helper()
+
+	objs := make([]runtime.Object, 0, len(nodes))
+	for _, node := range nodes {
+		objs = append(objs, node)
+	}
+	kube := kubefake.NewSimpleClientset(objs...)
+
+	opts := config.Options{
+		DefaultFloatingNetwork: testNetwork,
+		DefaultFloatingSubnet:  testSubnet,
+		FIPDeletionPolicy:      config.FIPDeletionPolicyKeep,
+		ClusterName:            testClusterName,
+		ProjectIDs:             []string{testProject},
+		EnableFloatingIPPools:  len(pools) > 0,
+	}
+	setOpts(&opts)
+
+	poolObjs := make([]runtime.Object, 0, len(pools))
+	for _, pool := range pools {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pool)
+		if err != nil {
+			t.Fatalf("failed to convert pool: %v", err)
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("FloatingIPPool"))
+		poolObjs = append(poolObjs, u)
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			v1alpha1.FloatingIPPoolResource:  "FloatingIPPoolList",
+			v1alpha1.FloatingIPClaimResource: "FloatingIPClaimList",
+		}, poolObjs...)
+
+	logger := log.NewNopLogger()
+	k8sFramework := frameworks.NewK8sFrameworkForClients(opts, kube, dynamicClient, logger)
+
+	openstack := fake.NewOpenStack(opts)
+	openstack.AddNetwork(testNetwork)
+	openstack.AddSubnet(testSubnet)
+	openstack.AddServer(testServerID, testNodeName, testProject, "10.0.0.1")
+
+	c := newController(opts, k8sFramework, openstack, logger)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	k8sFramework.Run(stopCh)
+	if !k8sFramework.WaitForCacheToSync(stopCh) {
+		t.Fatal("timed out waiting for caches to sync")
+	}
+
+	return &testEnv{controller: c, kube: kube, dynamic: dynamicClient, openstack: openstack}
+}
+
+func newTestNode(labels map[string]string) *corev1.Node {
+	nodeLabels := map[string]string{labelKubeFIPControllerEnabled: "true"}
+	for k, v := range labels {
+		nodeLabels[k] = v
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: testNodeName, Labels: nodeLabels},
+		Spec:       corev1.NodeSpec{ProviderID: providerPrefix + testServerID},
+	}
+}
+
+func (e *testEnv) getNode(t *testing.T) *corev1.Node {
+	t.Helper()
+	node, err := e.kube.CoreV1().Nodes().Get(context.Background(), testNodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	return node
+}
+
+// waitForClaim waits until the informer has seen a FloatingIPClaim of the node that matches the condition.
+func (e *testEnv) waitForClaim(t *testing.T, condition func(*v1alpha1.FloatingIPClaim) bool) *v1alpha1.FloatingIPClaim {
+	t.Helper()
+	node := e.getNode(t)
+	var claim *v1alpha1.FloatingIPClaim
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, time.Second, true, func(ctx context.Context) (bool, error) {
+		// A claim that is not known by the informer yet is read from the API server.
+		e.dynamic.ClearActions()
+		var err error
+		claim, err = e.controller.k8sFramework.GetOrCreateFloatingIPClaim(ctx, node)
+		return err == nil && len(e.dynamic.Actions()) == 0 && condition(claim), nil
+	})
+	if err != nil {
+		t.Fatalf("timed out waiting for the claim: %v", err)
+	}
+	return claim
+}
+
+// addTaggedFloatingIP adds an unassociated floating IP tagged with the given cluster.
+func (e *testEnv) addTaggedFloatingIP(floatingIP, projectID, description, clusterName string) *neutronfip.FloatingIP {
+	fip := e.openstack.AddFloatingIP(floatingIP, projectID, description, "")
+	fip.Tags = []string{frameworks.ClusterTag(clusterName)}
+	e.openstack.UpdateFloatingIP(fip)
+	return fip
+}
+
+func (e *testEnv) assertCondition(t *testing.T, status corev1.ConditionStatus, reason string) {
+	t.Helper()
+	for _, cond := range e.getNode(t).Status.Conditions {
+		if cond.Type != nodeConditionFloatingIPReady {
+			continue
+		}
+		if cond.Status != status || cond.Reason != reason {
+			t.Errorf("expected condition %s/%s, got %s/%s: %s", status, reason, cond.Status, cond.Reason, cond.Message)
+		}
+		return
+	}
+	t.Errorf("condition %s not found", nodeConditionFloatingIPReady)
+}
+
+func (e *testEnv) assertAssociated(t *testing.T, floatingIP, serverID string) {
+	t.Helper()
+	fip, err := e.openstack.GetFloatingIPByAddress(context.Background(), floatingIP)
+	if err != nil {
+		t.Fatalf("failed to get FIP %s: %v", floatingIP, err)
+	}
+	associatedServerID, err := e.openstack.GetAssociatedServerID(context.Background(), fip)
+	if err != nil {
+		t.Fatalf("failed to get server of FIP %s: %v", floatingIP, err)
+	}
+	if associatedServerID != serverID {
+		t.Errorf("expected FIP %s to be associated with server %q, got %q", floatingIP, serverID, associatedServerID)
+	}
+}
+
+func TestSyncHandlerCreatesAndAssociatesFIP(t *testing.T) {
+	env := newTestEnv(t, newTestNode(nil))
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := env.openstack.Calls("CreateFloatingIP"); n != 1 {
+		t.Errorf("expected 1 FIP to be created, got %d", n)
+	}
+	fips := env.openstack.FloatingIPs()
+	if len(fips) != 1 {
+		t.Fatalf("expected 1 FIP, got %d", len(fips))
+	}
+	if !frameworks.IsCreatedByController(&fips[0]) {
+		t.Errorf("expected FIP to be marked as created by the controller, got description %q", fips[0].Description)
+	}
+	if fips[0].ProjectID != testProject {
+		t.Errorf("expected FIP in project %s, got %s", testProject, fips[0].ProjectID)
+	}
+
+	if label := env.getNode(t).GetLabels()[labelExternalIP]; label != fips[0].FloatingIP {
+		t.Errorf("expected label %s=%s, got %q", labelExternalIP, fips[0].FloatingIP, label)
+	}
+	env.assertAssociated(t, fips[0].FloatingIP, testServerID)
+	env.assertCondition(t, corev1.ConditionTrue, eventReasonFIPAssociated)
+}
+
+func TestSyncHandlerUsesFIPFromLabel(t *testing.T) {
+	env := newTestEnv(t, newTestNode(map[string]string{labelExternalIP: "198.51.100.10"}))
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fips := env.openstack.FloatingIPs()
+	if len(fips) != 1 || fips[0].FloatingIP != "198.51.100.10" {
+		t.Fatalf("expected FIP 198.51.100.10 to be created, got %v", fips)
+	}
+	env.assertAssociated(t, "198.51.100.10", testServerID)
+}
+
+func TestSyncHandlerReusesFIPOfNodepool(t *testing.T) {
+	env := newTestEnv(t, newTestNode(map[string]string{
+		labelNodepoolName: "pool-a",
+		labelReuseFIPs:    "true",
+	}))
+	env.addTaggedFloatingIP("198.51.100.20", testProject, frameworks.FloatingIPDescription("pool-b"), testClusterName)
+	env.addTaggedFloatingIP("198.51.100.21", testProject, frameworks.FloatingIPDescription("pool-a"), testClusterName)
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := env.openstack.Calls("CreateFloatingIP"); n != 0 {
+		t.Errorf("expected no FIP to be created, got %d", n)
+	}
+	if label := env.getNode(t).GetLabels()[labelExternalIP]; label != "198.51.100.21" {
+		t.Errorf("expected the unassociated FIP of the nodepool to be reused, got %q", label)
+	}
+	env.assertAssociated(t, "198.51.100.21", testServerID)
+	env.assertAssociated(t, "198.51.100.20", "")
+}
+
+func TestSyncHandlerKeepsAlreadyAssociatedFIP(t *testing.T) {
+	env := newTestEnv(t, newTestNode(map[string]string{labelExternalIP: "198.51.100.30"}))
+	env.openstack.AddFloatingIP("198.51.100.30", testProject, "allocated manually", testServerID)
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := env.openstack.Calls("CreateFloatingIP"); n != 0 {
+		t.Errorf("expected no FIP to be created, got %d", n)
+	}
+	if n := env.openstack.Calls("AssociateFloatingIP"); n != 0 {
+		t.Errorf("expected no FIP to be associated, got %d", n)
+	}
+	env.assertAssociated(t, "198.51.100.30", testServerID)
+	env.assertCondition(t, corev1.ConditionTrue, eventReasonFIPAssociated)
+}
+
+func TestSyncHandlerFailsIfFIPIsAssociatedElsewhere(t *testing.T) {
+	env := newTestEnv(t, newTestNode(map[string]string{labelExternalIP: "198.51.100.40"}))
+	env.openstack.AddServer("server-2", "node-2", testProject, "10.0.0.2")
+	env.openstack.AddFloatingIP("198.51.100.40", testProject, "allocated manually", "server-2")
+
+	err := env.controller.syncHandler(context.Background(), testNodeName)
+	if !frameworks.IsFIPAssociatedElsewhere(err) {
+		t.Fatalf("expected FIPAssociatedElsewhereError, got %v", err)
+	}
+
+	if n := env.openstack.Calls("AssociateFloatingIP"); n != 0 {
+		t.Errorf("expected no FIP to be associated, got %d", n)
+	}
+	env.assertAssociated(t, "198.51.100.40", "server-2")
+	env.assertCondition(t, corev1.ConditionFalse, eventReasonFIPAlreadyAssociatedElsewhere)
+}
+
+func TestSyncHandlerIgnoresDisabledNode(t *testing.T) {
+	node := newTestNode(nil)
+	node.Labels[labelKubeFIPControllerEnabled] = "false"
+	env := newTestEnv(t, node)
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := env.openstack.Calls("GetOrCreateFloatingIP"); n != 0 {
+		t.Errorf("expected no FIP to be requested, got %d", n)
+	}
+}
+
+func TestSyncHandlerReportsMissingServer(t *testing.T) {
+	node := newTestNode(nil)
+	node.Name = "node-without-server"
+	node.Spec.ProviderID = providerPrefix + "unknown"
+	env := newTestEnv(t, node)
+
+	err := env.controller.syncHandler(context.Background(), node.GetName())
+	if !frameworks.IsServerNotFound(err) {
+		t.Fatalf("expected server not found error, got %v", err)
+	}
+	if n := env.openstack.Calls("GetOrCreateFloatingIP"); n != 0 {
+		t.Errorf("expected no FIP to be requested, got %d", n)
+	}
+}
+
+func TestHandleErrorDoesNotRetryPermanentErrors(t *testing.T) {
+	env := newTestEnv(t)
+	env.controller.opts.MaxRetries = 5
+
+	env.controller.handleError(&frameworks.NotFoundError{Resource: "network", Name: testNetwork}, testNodeName)
+	if n := env.controller.queue.NumRequeues(testNodeName); n != 0 {
+		t.Errorf("expected permanent error not to be retried, got %d requeues", n)
+	}
+
+	env.controller.handleError(&frameworks.NotFoundError{Resource: "server", Name: testNodeName}, testNodeName)
+	if n := env.controller.queue.NumRequeues(testNodeName); n != 1 {
+		t.Errorf("expected transient error to be retried, got %d requeues", n)
+	}
+
+	env.controller.handleError(errors.New("something went wrong"), testNodeName)
+	if n := env.controller.queue.NumRequeues(testNodeName); n != 2 {
+		t.Errorf("expected unclassified error to be retried, got %d requeues", n)
+	}
+
+	env.controller.handleError(nil, testNodeName)
+	if n := env.controller.queue.NumRequeues(testNodeName); n != 0 {
+		t.Errorf("expected success to reset the retries, got %d requeues", n)
+	}
+}
+
+func TestProcessNextItemDropsItemsOnShutdown(t *testing.T) {
+	env := newTestEnv(t, newTestNode(nil))
+	env.controller.queue.Add(testNodeName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if env.controller.processNextItem(ctx, context.Background()) {
+		t.Error("expected the worker to stop after the shutdown began")
+	}
+	if n := env.openstack.Calls("GetOrCreateFloatingIP"); n != 0 {
+		t.Errorf("expected no node to be synced, got %d FIP requests", n)
+	}
+	if n := env.controller.queue.Len(); n != 0 {
+		t.Errorf("expected the item to be dropped, got %d queued", n)
+	}
+}
+
+func TestDrainQueueWaitsForInFlightSyncs(t *testing.T) {
+	env := newTestEnv(t)
+	env.controller.opts.ShutdownTimeout = time.Minute
+	env.controller.queue.Add(testNodeName)
+	key, _ := env.controller.queue.Get()
+
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		env.controller.queue.Done(key)
+	}()
+
+	env.controller.drainQueue(cancelWork)
+	if workCtx.Err() != nil {
+		t.Error("expected the in-flight sync not to be cancelled")
+	}
+}
+
+func TestDrainQueueCancelsSyncsAfterShutdownTimeout(t *testing.T) {
+	env := newTestEnv(t)
+	env.controller.opts.ShutdownTimeout = 10 * time.Millisecond
+	env.controller.queue.Add(testNodeName)
+	key, _ := env.controller.queue.Get()
+
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+	go func() {
+		<-workCtx.Done()
+		env.controller.queue.Done(key)
+	}()
+
+	env.controller.drainQueue(cancelWork)
+	if workCtx.Err() == nil {
+		t.Error("expected the in-flight sync to be cancelled")
+	}
+}
+
+func TestAuditSkipsOrphanedFIPsWithinGracePeriod(t *testing.T) {
+	env := newTestEnv(t)
+	env.controller.opts.GCGracePeriod = time.Hour
+
+	recent := env.addTaggedFloatingIP("192.0.2.1", testProject, frameworks.FloatingIPDescription(""), testClusterName)
+	recent.CreatedAt = time.Now()
+	env.openstack.UpdateFloatingIP(recent)
+	old := env.addTaggedFloatingIP("192.0.2.2", testProject, frameworks.FloatingIPDescription(""), testClusterName)
+	old.CreatedAt = time.Now().Add(-2 * time.Hour)
+	env.openstack.UpdateFloatingIP(old)
+
+	report, err := env.controller.Audit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Drifts) != 1 || report.Drifts[0].FloatingIP != old.FloatingIP {
+		t.Errorf("expected only FIP %s to be reported as orphaned, got %+v", old.FloatingIP, report.Drifts)
+	}
+}
+
+func TestSyncHandlerDoesNotReuseFIPOfOtherCluster(t *testing.T) {
+	env := newTestEnv(t, newTestNode(map[string]string{
+		labelNodepoolName: "pool-a",
+		labelReuseFIPs:    "true",
+	}))
+	env.addTaggedFloatingIP("198.51.100.50", testProject, frameworks.FloatingIPDescription("pool-a"), "cluster-b")
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := env.openstack.Calls("CreateFloatingIP"); n != 1 {
+		t.Errorf("expected a FIP to be created instead of reusing the one of another cluster, got %d", n)
+	}
+	if label := env.getNode(t).GetLabels()[labelExternalIP]; label == "198.51.100.50" {
+		t.Errorf("expected the FIP of another cluster not to be reused")
+	}
+	env.assertAssociated(t, "198.51.100.50", "")
+}
+
+func TestCollectGarbageDeletesOnlyFIPsOfThisCluster(t *testing.T) {
+	env := newTestEnv(t)
+
+	own := env.addTaggedFloatingIP("192.0.2.1", testProject, frameworks.FloatingIPDescription(""), testClusterName)
+	otherCluster := env.addTaggedFloatingIP("192.0.2.2", testProject, frameworks.FloatingIPDescription(""), "cluster-b")
+	otherProject := env.addTaggedFloatingIP("192.0.2.3", "project-2", frameworks.FloatingIPDescription(""), testClusterName)
+
+	env.controller.collectGarbage(context.Background())
+
+	remaining := make(map[string]struct{})
+	for _, fip := range env.openstack.FloatingIPs() {
+		remaining[fip.FloatingIP] = struct{}{}
+	}
+	if _, ok := remaining[own.FloatingIP]; ok {
+		t.Errorf("expected orphaned FIP %s of this cluster to be deleted", own.FloatingIP)
+	}
+	for _, fip := range []*neutronfip.FloatingIP{otherCluster, otherProject} {
+		if _, ok := remaining[fip.FloatingIP]; !ok {
+			t.Errorf("expected FIP %s of another cluster or project to be kept", fip.FloatingIP)
+		}
+	}
+}
+
+func TestAuditDoesNotReportFIPsOfOtherClusters(t *testing.T) {
+	env := newTestEnv(t)
+	env.addTaggedFloatingIP("192.0.2.1", testProject, frameworks.FloatingIPDescription(""), "cluster-b")
+
+	report, err := env.controller.Audit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Drifts) != 0 {
+		t.Errorf("expected the FIP of another cluster not to be reported, got %+v", report.Drifts)
+	}
+}
+
+func TestSyncHandlerAllocatesNextPoolFIPIfReservedOneIsTaken(t *testing.T) {
+	pool := &v1alpha1.FloatingIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: v1alpha1.FloatingIPPoolSpec{
+			FloatingIPs:  []string{"198.51.100.60", "198.51.100.61"},
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{labelKubeFIPControllerEnabled: "true"}},
+		},
+	}
+	env := newTestEnvWithPools(t, []*v1alpha1.FloatingIPPool{pool}, newTestNode(nil))
+	node := env.getNode(t)
+
+	// The node reserved the first FIP of the pool, which is then taken by another server.
+	if fip, err := env.controller.allocateFloatingIPFromPool(pool, node, nil); err != nil || fip != "198.51.100.60" {
+		t.Fatalf("expected 198.51.100.60 to be allocated, got %q, %v", fip, err)
+	}
+	env.openstack.AddServer("server-2", "node-2", testProject, "10.0.0.2")
+	env.openstack.AddFloatingIP("198.51.100.60", testProject, "allocated manually", "server-2")
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if label := env.getNode(t).GetLabels()[labelExternalIP]; label != "198.51.100.61" {
+		t.Errorf("expected the next FIP of the pool to be used, got %q", label)
+	}
+	env.assertAssociated(t, "198.51.100.61", testServerID)
+	env.assertAssociated(t, "198.51.100.60", "server-2")
+	if nodeName, ok := env.controller.poolReservations["198.51.100.60"]; ok {
+		t.Errorf("expected the reservation of the taken FIP to be released, got %s", nodeName)
+	}
+}
+
+func TestStartWorkersUpdatesEnabledNodesWithoutFIPMetric(t *testing.T) {
+	env := newTestEnv(t, newTestNode(nil))
+	env.controller.opts.RecheckInterval = time.Hour
+	metrics.MetricEnabledNodesWithoutFIP.Set(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env.controller.startWorkers(ctx, ctx, 0)
+
+	if n := testutil.ToFloat64(metrics.MetricEnabledNodesWithoutFIP); n != 1 {
+		t.Errorf("expected 1 enabled node without FIP right after the start, got %v", n)
+	}
+}
+
+func TestFinalizedNodeIsNotCleanedUpAgainAfterDeletion(t *testing.T) {
+	node := newTestNode(map[string]string{labelExternalIP: "198.51.100.70"})
+	node.SetUID("node-uid")
+	node.SetFinalizers([]string{finalizerFIPCleanup})
+	node.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	env := newTestEnv(t, node)
+	env.controller.opts.FIPDeletionPolicy = config.FIPDeletionPolicyDisassociate
+	env.openstack.AddFloatingIP("198.51.100.70", testProject, "allocated manually", "")
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := env.openstack.Calls("DisassociateFloatingIP"); n != 1 {
+		t.Fatalf("expected the finalizer to release the FIP once, got %d", n)
+	}
+
+	if err := env.kube.CoreV1().Nodes().Delete(context.Background(), testNodeName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete node: %v", err)
+	}
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, time.Second, true, func(context.Context) (bool, error) {
+		_, exists, err := env.controller.k8sFramework.GetNodeFromIndexerByKey(testNodeName)
+		return !exists, err
+	}); err != nil {
+		t.Fatalf("node was not removed from the cache: %v", err)
+	}
+
+	if _, ok := env.controller.getDeletedNode(testNodeName); ok {
+		t.Error("expected the finalized node not to be cleaned up again")
+	}
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := env.openstack.Calls("DisassociateFloatingIP"); n != 1 {
+		t.Errorf("expected the FIP to be released only once, got %d", n)
+	}
+}
+
+func TestSyncHandlerUpdatesClaimOnlyIfStatusChanged(t *testing.T) {
+	env := newTestEnvWithOptions(t, func(opts *config.Options) { opts.EnableFloatingIPClaims = true }, nil, newTestNode(nil))
+
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claim := env.waitForClaim(t, func(claim *v1alpha1.FloatingIPClaim) bool { return claim.Status.FloatingIP != "" })
+	if claim.Status.LastSyncTime.IsZero() {
+		t.Error("expected the last sync time to be set")
+	}
+
+	env.dynamic.ClearActions()
+	if err := env.controller.syncHandler(context.Background(), testNodeName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, action := range env.dynamic.Actions() {
+		t.Errorf("expected no request for an unchanged claim, got %s %s", action.GetVerb(), action.GetResource().Resource)
+	}
+}
@@ -0,0 +1,58 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/kube-fip-controller/pkg/frameworks"
+)
+
+const (
+	eventReasonFIPCreated                    = "FIPCreated"
+	eventReasonFIPAssociated                 = "FIPAssociated"
+	eventReasonFIPAlreadyAssociatedElsewhere = "FIPAlreadyAssociatedElsewhere"
+	eventReasonServerNotFound                = "ServerNotFound"
+	eventReasonNetworkNotFound               = "NetworkNotFound"
+	eventReasonSubnetNotFound                = "SubnetNotFound"
+)
+
+// recordErrorEvent records a warning event on the node for errors the user of the node can act upon.
+func (c *Controller) recordErrorEvent(node *corev1.Node, err error) {
+	if reason, ok := getReasonForError(err); ok {
+		c.k8sFramework.RecordNodeEvent(node, corev1.EventTypeWarning, reason, "%s", err.Error())
+	}
+}
+
+// getReasonForError returns the reason for known errors.
+func getReasonForError(err error) (string, bool) {
+	switch {
+	case frameworks.IsFIPAssociatedElsewhere(err):
+		return eventReasonFIPAlreadyAssociatedElsewhere, true
+	case frameworks.IsServerNotFound(err):
+		return eventReasonServerNotFound, true
+	case frameworks.IsNetworkNotFound(err):
+		return eventReasonNetworkNotFound, true
+	case frameworks.IsSubnetNotFound(err):
+		return eventReasonSubnetNotFound, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,144 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/log/level"
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/kube-fip-controller/pkg/frameworks"
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+// collectGarbage finds FIPs allocated by the controller that are not referenced by any node and deletes them.
+func (c *Controller) collectGarbage(ctx context.Context) {
+	fips, err := c.osFramework.ListFloatingIPsCreatedByController(ctx)
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to list FIPs for garbage collection", "err", err) //nolint:errcheck
+		return
+	}
+
+	referencedFIPs, reusedNodepools := c.getReferencedFIPsAndReusedNodepools()
+	if err := c.addFloatingIPPoolFIPs(referencedFIPs); err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to list floating ip pools for garbage collection", "err", err) //nolint:errcheck
+		return
+	}
+
+	orphanCount := 0
+	for _, fip := range fips {
+		if !isOrphanedFIP(&fip, referencedFIPs, reusedNodepools) {
+			continue
+		}
+
+		lastUpdate := getLastUpdate(&fip)
+		if time.Since(lastUpdate) < c.opts.GCGracePeriod {
+			continue
+		}
+
+		orphanCount++
+		_ = level.Info(c.logger).Log("msg", "found orphaned FIP", "fip", fip.FloatingIP, "id", fip.ID, "portID", fip.PortID, "lastUpdate", lastUpdate) //nolint:errcheck
+
+		if c.opts.GCDryRun {
+			continue
+		}
+
+		if fip.PortID != "" {
+			_ = level.Info(c.logger).Log("msg", "not deleting orphaned FIP as it is still associated", "fip", fip.FloatingIP, "id", fip.ID, "portID", fip.PortID) //nolint:errcheck
+			continue
+		}
+
+		if err := c.osFramework.DeleteFloatingIP(ctx, &fip); err != nil {
+			_ = level.Error(c.logger).Log("msg", "failed to delete orphaned FIP", "fip", fip.FloatingIP, "id", fip.ID, "err", err) //nolint:errcheck
+		}
+	}
+
+	metrics.MetricOrphanedFIPs.Set(float64(orphanCount))
+	_ = level.Info(c.logger).Log("msg", "completed garbage collection", "orphans", orphanCount, "dryRun", c.opts.GCDryRun) //nolint:errcheck
+}
+
+// getLastUpdate returns the time the FIP was last updated or created.
+func getLastUpdate(fip *neutronfip.FloatingIP) time.Time {
+	if fip.UpdatedAt.IsZero() {
+		return fip.CreatedAt
+	}
+	return fip.UpdatedAt
+}
+
+// isOrphanedFIP checks whether a FIP allocated by the controller is neither referenced nor kept for reuse by a nodepool.
+func isOrphanedFIP(fip *neutronfip.FloatingIP, referencedFIPs, reusedNodepools map[string]struct{}) bool {
+	if _, ok := referencedFIPs[fip.FloatingIP]; ok {
+		return false
+	}
+
+	// Unassociated FIPs of nodepools with reuse enabled are kept on purpose.
+	_, ok := reusedNodepools[frameworks.GetNodepoolOfFloatingIP(fip)]
+	return !ok
+}
+
+// getReferencedFIPsAndReusedNodepools returns the FIPs referenced by nodes, including deleted nodes pending cleanup,
+// and the nodepools that reuse FIPs.
+func (c *Controller) getReferencedFIPsAndReusedNodepools() (referencedFIPs, reusedNodepools map[string]struct{}) {
+	referencedFIPs = make(map[string]struct{})
+	reusedNodepools = make(map[string]struct{})
+
+	addNode := func(node *corev1.Node) {
+		if val, ok := getLabelValue(node, labelExternalIP); ok && val != "" {
+			referencedFIPs[val] = struct{}{}
+		}
+		nodepool, ok := getLabelValue(node, labelNodepoolName)
+		if !ok || nodepool == "" {
+			return
+		}
+		if val, ok := getLabelValue(node, labelReuseFIPs); ok && val == "true" {
+			reusedNodepools[nodepool] = struct{}{}
+		}
+	}
+
+	for _, obj := range c.k8sFramework.GetNodeInformerStore().List() {
+		if node, ok := obj.(*corev1.Node); ok {
+			addNode(node)
+		}
+	}
+
+	c.deletedNodesMtx.Lock()
+	defer c.deletedNodesMtx.Unlock()
+	for _, node := range c.deletedNodes {
+		addNode(node)
+	}
+	return referencedFIPs, reusedNodepools
+}
+
+// addFloatingIPPoolFIPs adds the FIPs of all pools to the referenced FIPs. Unassigned FIPs of pools are kept for future nodes.
+func (c *Controller) addFloatingIPPoolFIPs(referencedFIPs map[string]struct{}) error {
+	pools, err := c.k8sFramework.ListFloatingIPPools()
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		for _, fip := range pool.Spec.FloatingIPs {
+			referencedFIPs[fip] = struct{}{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sapcc/kube-fip-controller/pkg/health"
+)
+
+const tokenValidationTimeout = 10 * time.Second
+
+// AddHealthChecks adds the liveness and readiness checks of the controller.
+func (c *Controller) AddHealthChecks(checker *health.Checker) {
+	checker.AddReadinessCheck("informer-sync", c.checkInformerSync)
+	checker.AddReadinessCheck("openstack-token", c.checkOpenStackToken)
+	checker.AddLivenessCheck("workers", c.checkWorkers)
+}
+
+func (c *Controller) checkInformerSync() error {
+	if !c.k8sFramework.HasSynced() {
+		return errors.New("informer caches not synced")
+	}
+	return nil
+}
+
+func (c *Controller) checkOpenStackToken() error {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenValidationTimeout)
+	defer cancel()
+	return c.osFramework.ValidateToken(ctx)
+}
+
+// checkWorkers fails if the queue is not empty but the workers did not process anything within the liveness timeout.
+// Standby replicas do not run workers and are always considered alive.
+func (c *Controller) checkWorkers() error {
+	if !c.workersStarted.Load() || c.queue.Len() == 0 {
+		return nil
+	}
+
+	lastActivity := time.Unix(0, c.lastWorkerActivity.Load())
+	if since := time.Since(lastActivity); since > c.opts.LivenessTimeout {
+		return fmt.Errorf("no work processed for %s with %d items queued", since.Round(time.Second), c.queue.Len())
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-kit/log/level"
+	"k8s.io/client-go/tools/leaderelection"
+
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+// runWithLeaderElection starts the workers once this replica becomes the leader.
+// Informers are already running, so standby replicas keep warm caches.
+// The returned function releases the leadership. It is called after the workers stopped, so no two replicas sync nodes at the same time.
+func (c *Controller) runWithLeaderElection(ctx, workCtx context.Context, threadiness int) (releaseLeadership func()) {
+	identity, err := os.Hostname()
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to get hostname for leader election", "err", err) //nolint:errcheck
+		return func() {}
+	}
+	metrics.MetricLeader.WithLabelValues(identity).Set(0)
+
+	leaderCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		leaderelection.RunOrDie(leaderCtx, leaderelection.LeaderElectionConfig{
+			Lock:            c.k8sFramework.NewLeaseLock(c.opts.LeaderElectLeaseName, c.opts.LeaderElectNamespace, identity),
+			LeaseDuration:   c.opts.LeaderElectLeaseDuration,
+			RenewDeadline:   c.opts.LeaderElectRenewDeadline,
+			RetryPeriod:     c.opts.LeaderElectRetryPeriod,
+			ReleaseOnCancel: true,
+			Name:            c.opts.LeaderElectLeaseName,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(context.Context) {
+					_ = level.Info(c.logger).Log("msg", "started leading", "identity", identity) //nolint:errcheck
+					metrics.MetricLeader.WithLabelValues(identity).Set(1)
+					c.startWorkers(ctx, workCtx, threadiness)
+				},
+				OnStoppedLeading: func() {
+					metrics.MetricLeader.WithLabelValues(identity).Set(0)
+					if leaderCtx.Err() != nil {
+						_ = level.Info(c.logger).Log("msg", "released leadership", "identity", identity) //nolint:errcheck
+						return
+					}
+					// Workers cannot be stopped without shutting down the queue, so restart to become a standby replica.
+					_ = level.Error(c.logger).Log("msg", "lost leadership. exiting", "identity", identity) //nolint:errcheck
+					os.Exit(1)
+				},
+				OnNewLeader: func(leader string) {
+					_ = level.Info(c.logger).Log("msg", "new leader elected", "leader", leader) //nolint:errcheck
+				},
+			},
+		})
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,57 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+// updateNodeFIPMetrics reflects the result of the node's sync in the per node metrics.
+func (c *Controller) updateNodeFIPMetrics(node *corev1.Node, result *syncResult, syncErr error) {
+	var floatingIP, serverID string
+	if result.fip != nil {
+		floatingIP = result.fip.FloatingIP
+	}
+	if result.server != nil {
+		serverID = result.server.ID
+	}
+
+	metrics.SetNodeFIPMetrics(
+		node.GetName(), floatingIP, result.floatingNetworkName, result.floatingSubnetName, result.nodepool, serverID,
+		syncErr == nil && result.fip != nil,
+	)
+}
+
+// updateEnabledNodesWithoutFIPMetric counts the enabled nodes without the externalIP label. It is called when the workers start and once per recheck interval.
+func (c *Controller) updateEnabledNodesWithoutFIPMetric() {
+	count := 0
+	for _, obj := range c.k8sFramework.GetNodeInformerStore().List() {
+		node, ok := obj.(*corev1.Node)
+		if !ok || !isEnabled(node) {
+			continue
+		}
+		if val, ok := getLabelValue(node, labelExternalIP); !ok || val == "" {
+			count++
+		}
+	}
+	metrics.MetricEnabledNodesWithoutFIP.Set(float64(count))
+}
@@ -0,0 +1,190 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-kit/log/level"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/sapcc/kube-fip-controller/pkg/apis/v1alpha1"
+)
+
+// getFloatingIPPoolForNode returns the first FloatingIPPool, ordered by name, that selects the node or nil.
+func (c *Controller) getFloatingIPPoolForNode(node *corev1.Node) (*v1alpha1.FloatingIPPool, error) {
+	pools, err := c.listSortedFloatingIPPools()
+	if err != nil {
+		return nil, err
+	}
+	return c.selectFloatingIPPool(pools, node), nil
+}
+
+func (c *Controller) listSortedFloatingIPPools() ([]*v1alpha1.FloatingIPPool, error) {
+	pools, err := c.k8sFramework.ListFloatingIPPools()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(pools, func(a, b *v1alpha1.FloatingIPPool) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return pools, nil
+}
+
+// selectFloatingIPPool returns the first of the sorted pools that selects the node or nil.
+func (c *Controller) selectFloatingIPPool(pools []*v1alpha1.FloatingIPPool, node *corev1.Node) *v1alpha1.FloatingIPPool {
+	for _, pool := range pools {
+		ok, err := poolSelectsNode(pool, node)
+		if err != nil {
+			_ = level.Error(c.logger).Log("msg", "invalid node selector in floating ip pool", "pool", pool.GetName(), "err", err) //nolint:errcheck
+			continue
+		}
+		if ok {
+			return pool
+		}
+	}
+	return nil
+}
+
+// allocateFloatingIPFromPool returns a FIP from the pool's list that is neither used nor reserved by another node nor excluded.
+func (c *Controller) allocateFloatingIPFromPool(pool *v1alpha1.FloatingIPPool, node *corev1.Node, excludedFIPs map[string]struct{}) (string, error) {
+	c.poolReservationsMtx.Lock()
+	defer c.poolReservationsMtx.Unlock()
+
+	usedFIPs, _ := c.getReferencedFIPsAndReusedNodepools()
+	for fip := range excludedFIPs {
+		usedFIPs[fip] = struct{}{}
+	}
+	for fip, nodeName := range c.poolReservations {
+		if _, excluded := excludedFIPs[fip]; !excluded && nodeName == node.GetName() && slices.Contains(pool.Spec.FloatingIPs, fip) {
+			return fip, nil
+		}
+
+		// The reservation is obsolete once the node carries the label or is gone.
+		reservingNode, exists, err := c.k8sFramework.GetNodeFromIndexerByKey(nodeName)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			delete(c.poolReservations, fip)
+			continue
+		}
+		if val, ok := getLabelValue(reservingNode, labelExternalIP); ok && val == fip {
+			delete(c.poolReservations, fip)
+			continue
+		}
+		usedFIPs[fip] = struct{}{}
+	}
+
+	for _, fip := range pool.Spec.FloatingIPs {
+		if _, ok := usedFIPs[fip]; ok {
+			continue
+		}
+		c.poolReservations[fip] = node.GetName()
+		_ = level.Info(c.logger).Log("msg", "allocated FIP from pool", "node", node.GetName(), "pool", pool.GetName(), "fip", fip) //nolint:errcheck
+		return fip, nil
+	}
+	return "", fmt.Errorf("no free FIP left in pool %s", pool.GetName())
+}
+
+// releasePoolReservation releases the node's reservation of the FIP, e.g. because it is associated with another server.
+func (c *Controller) releasePoolReservation(node *corev1.Node, fip string) {
+	c.poolReservationsMtx.Lock()
+	defer c.poolReservationsMtx.Unlock()
+
+	if c.poolReservations[fip] == node.GetName() {
+		delete(c.poolReservations, fip)
+	}
+}
+
+// updateFloatingIPPoolStatuses counts the nodes and allocated FIPs of all FloatingIPPools and updates their status if it changed.
+// It is called once per recheck interval rather than per node sync, as every status update is an event for the pool informer.
+func (c *Controller) updateFloatingIPPoolStatuses(ctx context.Context) {
+	pools, err := c.listSortedFloatingIPPools()
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to list floating ip pools", "err", err) //nolint:errcheck
+		return
+	}
+
+	// Nodes are only counted for their effective pool.
+	statuses := make(map[string]*v1alpha1.FloatingIPPoolStatus, len(pools))
+	for _, pool := range pools {
+		statuses[pool.GetName()] = &v1alpha1.FloatingIPPoolStatus{}
+	}
+	for _, obj := range c.k8sFramework.GetNodeInformerStore().List() {
+		node, ok := obj.(*corev1.Node)
+		if !ok || !isEnabled(node) {
+			continue
+		}
+		pool := c.selectFloatingIPPool(pools, node)
+		if pool == nil {
+			continue
+		}
+
+		status := statuses[pool.GetName()]
+		status.Nodes++
+		val, ok := getLabelValue(node, labelExternalIP)
+		if !ok || val == "" {
+			continue
+		}
+		if len(pool.Spec.FloatingIPs) == 0 || slices.Contains(pool.Spec.FloatingIPs, val) {
+			status.Allocated++
+		}
+	}
+
+	for _, pool := range pools {
+		if err := c.updateFloatingIPPoolStatus(ctx, pool, *statuses[pool.GetName()]); err != nil {
+			_ = level.Error(c.logger).Log("msg", "failed to update floating ip pool status", "pool", pool.GetName(), "err", err) //nolint:errcheck
+		}
+	}
+}
+
+// updateFloatingIPPoolStatus updates the status of the pool with the given counts if it changed.
+func (c *Controller) updateFloatingIPPoolStatus(ctx context.Context, pool *v1alpha1.FloatingIPPool, status v1alpha1.FloatingIPPoolStatus) error {
+	if len(pool.Spec.FloatingIPs) > 0 {
+		free := max(len(pool.Spec.FloatingIPs)-status.Allocated, 0)
+		status.Free = &free
+	}
+
+	status.LastUpdateTime = pool.Status.LastUpdateTime
+	if apiequality.Semantic.DeepEqual(status, pool.Status) {
+		return nil
+	}
+
+	status.LastUpdateTime = metav1.Now()
+	newPool := pool.DeepCopy()
+	newPool.Status = status
+	return c.k8sFramework.UpdateFloatingIPPoolStatus(ctx, newPool)
+}
+
+func poolSelectsNode(pool *v1alpha1.FloatingIPPool, node *corev1.Node) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(pool.Spec.NodeSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(node.GetLabels())), nil
+}
@@ -0,0 +1,86 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+)
+
+// loadAuthConfig reads the OpenStack credentials from the clouds.yaml or the configuration file into the options.
+func loadAuthConfig(opts config.Options) (config.Options, error) {
+	switch {
+	case opts.OSCloud != "":
+		cloud, err := config.ReadCloudConfig(opts.OSCloud, opts.OSClientConfigFile)
+		if err != nil {
+			return opts, err
+		}
+		opts.Cloud = cloud
+	case opts.ConfigPath != "":
+		authConfig, err := config.ReadAuthConfig(opts.ConfigPath)
+		if err != nil {
+			return opts, err
+		}
+		opts.Auth = authConfig
+	default:
+		return opts, errors.New("either --config or --os-cloud is required")
+	}
+	return opts, nil
+}
+
+// getConfigFilePaths returns the files containing OpenStack credentials.
+func (c *Controller) getConfigFilePaths() []string {
+	if c.opts.OSCloud != "" {
+		return config.GetCloudsFilePaths(c.opts.OSClientConfigFile)
+	}
+
+	filePaths := []string{c.opts.ConfigPath}
+	if c.opts.Auth == nil {
+		return filePaths
+	}
+	for _, filePath := range []string{c.opts.Auth.PasswordFile, c.opts.Auth.CAFile, c.opts.Auth.CertFile, c.opts.Auth.KeyFile} {
+		if filePath != "" {
+			filePaths = append(filePaths, filePath)
+		}
+	}
+	return filePaths
+}
+
+// reloadConfig re-reads the OpenStack credentials and replaces the clients. Queued work is not affected.
+// The previous credentials are kept if the new ones are invalid and the reload is retried with the next poll.
+func (c *Controller) reloadConfig(ctx context.Context) error {
+	_ = level.Info(c.logger).Log("msg", "configuration changed. reloading OpenStack credentials") //nolint:errcheck
+
+	opts, err := loadAuthConfig(c.opts)
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to read configuration. keeping previous credentials", "err", err) //nolint:errcheck
+		return err
+	}
+
+	if err := c.osFramework.Reload(ctx, opts); err != nil {
+		_ = level.Error(c.logger).Log("msg", "failed to reload OpenStack credentials. keeping previous credentials", "err", err) //nolint:errcheck
+		return err
+	}
+	return nil
+}
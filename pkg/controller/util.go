@@ -20,8 +20,11 @@
 package controller
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -54,3 +57,20 @@ func getLabelValue(obj interface{}, lblKey string) (string, bool) {
 	val, ok := lbl[lblKey]
 	return val, ok
 }
+
+func isEnabled(node *corev1.Node) bool {
+	val, ok := getLabelValue(node, labelKubeFIPControllerEnabled)
+	return ok && val == "true"
+}
+
+func hasFinalizer(node *corev1.Node, finalizer string) bool {
+	return slices.Contains(node.GetFinalizers(), finalizer)
+}
+
+// withTimeout returns a context that is cancelled after the timeout. A timeout of 0 disables it.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
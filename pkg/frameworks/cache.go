@@ -0,0 +1,138 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+const (
+	cacheNetworkIDs   = "network_ids"
+	cacheSubnetIDs    = "subnet_ids"
+	cacheServerByID   = "servers_by_id"
+	cacheServerByName = "servers_by_name"
+
+	cacheResultHit  = "hit"
+	cacheResultMiss = "miss"
+
+	// cacheSweepInterval is the minimum interval between removing expired entries.
+	cacheSweepInterval = time.Minute
+)
+
+// ttlCache caches values and not found errors by key until their TTL expired.
+// Expired entries are removed when new entries are added, so the cache does not grow with the churn of servers.
+type ttlCache[V any] struct {
+	name      string
+	mtx       sync.Mutex
+	entries   map[string]cacheEntry[V]
+	lastSweep time.Time
+}
+
+type cacheEntry[V any] struct {
+	value     V
+	err       error
+	expiresAt time.Time
+}
+
+func newTTLCache[V any](name string) *ttlCache[V] {
+	return &ttlCache[V]{
+		name:    name,
+		entries: make(map[string]cacheEntry[V]),
+	}
+}
+
+// getOrLoad returns the cached value or error for the key. Otherwise, the value is loaded and cached for the ttl.
+// Not found errors are cached for the negativeTTL, all other errors are not cached.
+func (c *ttlCache[V]) getOrLoad(key string, ttl, negativeTTL time.Duration, loadFunc func() (V, error)) (V, error) {
+	c.mtx.Lock()
+	entry, ok := c.entries[key]
+	c.mtx.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		metrics.MetricCacheRequests.WithLabelValues(c.name, cacheResultHit).Inc()
+		return entry.value, entry.err
+	}
+	metrics.MetricCacheRequests.WithLabelValues(c.name, cacheResultMiss).Inc()
+
+	value, err := loadFunc()
+	switch {
+	case err == nil && ttl > 0:
+		c.set(key, cacheEntry[V]{value: value, expiresAt: time.Now().Add(ttl)})
+	case isNotFoundError(err) && negativeTTL > 0:
+		c.set(key, cacheEntry[V]{err: err, expiresAt: time.Now().Add(negativeTTL)})
+	default:
+		c.delete(key)
+	}
+	return value, err
+}
+
+func (c *ttlCache[V]) set(key string, entry cacheEntry[V]) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries[key] = entry
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) < cacheSweepInterval {
+		return
+	}
+	c.lastSweep = now
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *ttlCache[V]) delete(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.entries, key)
+}
+
+// deleteFunc deletes all entries for which the given function returns true.
+func (c *ttlCache[V]) deleteFunc(del func(key string, value V) bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key, entry := range c.entries {
+		if del(key, entry.value) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *ttlCache[V]) purge() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = make(map[string]cacheEntry[V])
+}
+
+// isNotFoundError checks whether the resource was not found by name or the API responded with 404.
+func isNotFoundError(err error) bool {
+	return IsServerNotFound(err) || IsNetworkNotFound(err) || IsSubnetNotFound(err) || is404(err)
+}
+
+func is404(err error) bool {
+	return gophercloud.ResponseCodeIs(err, http.StatusNotFound)
+}
@@ -0,0 +1,141 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+)
+
+func TestTTLCacheCachesValues(t *testing.T) {
+	c := newTTLCache[string]("test")
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "id", nil
+	}
+
+	for range 3 {
+		if v, err := c.getOrLoad("name", time.Minute, time.Minute, load); err != nil || v != "id" {
+			t.Fatalf("expected id, got %q, %v", v, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected 1 load, got %d", loads)
+	}
+
+	c.delete("name")
+	if _, err := c.getOrLoad("name", time.Minute, time.Minute, load); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loads != 2 {
+		t.Errorf("expected reload after invalidation, got %d loads", loads)
+	}
+}
+
+func TestTTLCacheExpires(t *testing.T) {
+	c := newTTLCache[string]("test")
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "id", nil
+	}
+
+	_, _ = c.getOrLoad("name", time.Nanosecond, time.Minute, load) //nolint:errcheck
+	time.Sleep(time.Millisecond)
+	_, _ = c.getOrLoad("name", time.Nanosecond, time.Minute, load) //nolint:errcheck
+	if loads != 2 {
+		t.Errorf("expected expired entry to be reloaded, got %d loads", loads)
+	}
+}
+
+func TestTTLCacheCachesNotFoundErrors(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		wantLoads int
+	}{
+		"not found by name": {
+			err:       &NotFoundError{Resource: "network", Name: "name"},
+			wantLoads: 1,
+		},
+		"404": {
+			err:       gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusNotFound},
+			wantLoads: 1,
+		},
+		"other errors": {
+			err:       errors.New("connection refused"),
+			wantLoads: 2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTTLCache[string]("test")
+			loads := 0
+			load := func() (string, error) {
+				loads++
+				return "", tc.err
+			}
+
+			for range 2 {
+				if _, err := c.getOrLoad("name", time.Minute, time.Minute, load); err == nil {
+					t.Fatal("expected error")
+				}
+			}
+			if loads != tc.wantLoads {
+				t.Errorf("expected %d loads, got %d", tc.wantLoads, loads)
+			}
+		})
+	}
+}
+
+func TestTTLCacheDisabled(t *testing.T) {
+	c := newTTLCache[string]("test")
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "", &NotFoundError{Resource: "subnet", Name: "name"}
+	}
+
+	_, _ = c.getOrLoad("name", 0, 0, load) //nolint:errcheck
+	_, _ = c.getOrLoad("name", 0, 0, load) //nolint:errcheck
+	if loads != 2 {
+		t.Errorf("expected no caching with a TTL of 0, got %d loads", loads)
+	}
+}
+
+func TestTTLCacheRemovesExpiredEntries(t *testing.T) {
+	c := newTTLCache[string]("test")
+	c.entries["gone"] = cacheEntry[string]{value: "id", expiresAt: time.Now().Add(-time.Second)}
+
+	if _, err := c.getOrLoad("name", time.Minute, time.Minute, func() (string, error) { return "id", nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := c.entries["gone"]; ok {
+		t.Error("expected expired entry to be removed")
+	}
+	if _, ok := c.entries["name"]; !ok {
+		t.Error("expected new entry to be cached")
+	}
+}
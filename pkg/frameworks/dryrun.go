@@ -0,0 +1,50 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+// Actions skipped in dry run mode. Used as label of the metric.
+const (
+	dryRunActionCreateFIP            = "create_fip"
+	dryRunActionTagFIP               = "tag_fip"
+	dryRunActionAssociateFIP         = "associate_fip"
+	dryRunActionDisassociateFIP      = "disassociate_fip"
+	dryRunActionDeleteFIP            = "delete_fip"
+	dryRunActionLabelNode            = "label_node"
+	dryRunActionUpdateNodeFinalizers = "update_node_finalizers"
+	dryRunActionSetNodeCondition     = "set_node_condition"
+	dryRunActionRecordEvent          = "record_event"
+	dryRunActionUpdatePoolStatus     = "update_pool_status"
+	dryRunActionCreateClaim          = "create_claim"
+	dryRunActionUpdateClaimStatus    = "update_claim_status"
+)
+
+// logDryRun logs an action that was skipped due to the dry run and counts it.
+func logDryRun(logger log.Logger, action, msg string, keyvals ...interface{}) {
+	metrics.MetricDryRunActions.WithLabelValues(action).Inc()
+	//nolint:errcheck
+	_ = level.Info(logger).Log(append([]interface{}{"msg", msg, "dryRun", true}, keyvals...)...)
+}
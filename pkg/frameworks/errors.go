@@ -19,7 +19,56 @@
 
 package frameworks
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// classifiedError is implemented by errors that know whether retrying can succeed.
+type classifiedError interface {
+	// Permanent returns true if retrying cannot succeed without changes to the node or the cloud.
+	Permanent() bool
+}
+
+// IsPermanent checks whether the given error is permanent and should not be retried.
+// Such errors are surfaced via events and the node condition instead.
+func IsPermanent(err error) bool {
+	var classified classifiedError
+	return errors.As(err, &classified) && classified.Permanent()
+}
+
+// IsTransient checks whether the given error is caused by a temporary issue like a server error, a conflict,
+// throttling, a network error, a server of a new node, which does not exist yet, or a timeout while waiting for the node,
+// so retrying is likely to succeed.
+func IsTransient(err error) bool {
+	if err == nil || IsPermanent(err) {
+		return false
+	}
+	if IsServerNotFound(err) || wait.Interrupted(err) {
+		return true
+	}
+	if apierrors.IsConflict(err) || apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+
+	var respErr gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &respErr) {
+		return respErr.Actual >= http.StatusInternalServerError ||
+			respErr.Actual == http.StatusConflict ||
+			respErr.Actual == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
 
 // ErrFIPNotFound is raised if the FIP cannot be found.
 var ErrFIPNotFound = errors.New("FloatingIP not found")
@@ -31,3 +80,59 @@ func IsFIPNotFound(err error) bool {
 	}
 	return err.Error() == ErrFIPNotFound.Error()
 }
+
+// NotFoundError is raised if an OpenStack resource cannot be found by name.
+type NotFoundError struct {
+	Resource string
+	Name     string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no %s with name %s found", e.Resource, e.Name)
+}
+
+// Permanent is true for networks and subnets, which are configured. Servers of new nodes might not be found yet.
+func (e *NotFoundError) Permanent() bool {
+	return e.Resource != "server"
+}
+
+// IsServerNotFound checks whether the given error is a NotFoundError for a server.
+func IsServerNotFound(err error) bool {
+	return isNotFound(err, "server")
+}
+
+// IsNetworkNotFound checks whether the given error is a NotFoundError for a network.
+func IsNetworkNotFound(err error) bool {
+	return isNotFound(err, "network")
+}
+
+// IsSubnetNotFound checks whether the given error is a NotFoundError for a subnet.
+func IsSubnetNotFound(err error) bool {
+	return isNotFound(err, "subnet")
+}
+
+func isNotFound(err error, resource string) bool {
+	var notFoundErr *NotFoundError
+	return errors.As(err, &notFoundErr) && notFoundErr.Resource == resource
+}
+
+// FIPAssociatedElsewhereError is raised if the FIP is already associated with another server.
+type FIPAssociatedElsewhereError struct {
+	FloatingIP string
+	ServerID   string
+}
+
+func (e *FIPAssociatedElsewhereError) Error() string {
+	return fmt.Sprintf("FIP %s already associated with another server %s", e.FloatingIP, e.ServerID)
+}
+
+// Permanent is always true as the FIP has to be released manually.
+func (e *FIPAssociatedElsewhereError) Permanent() bool {
+	return true
+}
+
+// IsFIPAssociatedElsewhere checks whether the given error is a FIPAssociatedElsewhereError.
+func IsFIPAssociatedElsewhere(err error) bool {
+	var associatedErr *FIPAssociatedElsewhereError
+	return errors.As(err, &associatedErr)
+}
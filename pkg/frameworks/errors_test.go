@@ -0,0 +1,71 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestErrorClassification(t *testing.T) {
+	tests := map[string]struct {
+		err           error
+		wantPermanent bool
+		wantTransient bool
+	}{
+		"nil":                      {err: nil},
+		"network not found":        {err: &NotFoundError{Resource: "network", Name: "n"}, wantPermanent: true},
+		"wrapped subnet not found": {err: fmt.Errorf("sync: %w", &NotFoundError{Resource: "subnet", Name: "s"}), wantPermanent: true},
+		"server not found":         {err: &NotFoundError{Resource: "server", Name: "s"}, wantTransient: true},
+		"associated elsewhere":     {err: &FIPAssociatedElsewhereError{FloatingIP: "192.0.2.1", ServerID: "s"}, wantPermanent: true},
+		"500":                      {err: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusInternalServerError}, wantTransient: true},
+		"503":                      {err: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusServiceUnavailable}, wantTransient: true},
+		"409":                      {err: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusConflict}, wantTransient: true},
+		"429":                      {err: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusTooManyRequests}, wantTransient: true},
+		"400":                      {err: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusBadRequest}},
+		"timeout":                  {err: fmt.Errorf("request: %w", context.DeadlineExceeded), wantTransient: true},
+		"connection refused":       {err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, wantTransient: true},
+		"node conflict":            {err: apierrors.NewConflict(corev1.Resource("nodes"), "n", errors.New("modified")), wantTransient: true},
+		"node not found":           {err: apierrors.NewNotFound(corev1.Resource("nodes"), "n")},
+		"waiting for node":         {err: fmt.Errorf("labels: %w", wait.ErrorInterrupted(errors.New("timed out"))), wantTransient: true},
+		"unknown":                  {err: errors.New("something went wrong")},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsPermanent(tc.err); got != tc.wantPermanent {
+				t.Errorf("expected IsPermanent to be %t, got %t", tc.wantPermanent, got)
+			}
+			if got := IsTransient(tc.err); got != tc.wantTransient {
+				t.Errorf("expected IsTransient to be %t, got %t", tc.wantTransient, got)
+			}
+		})
+	}
+}
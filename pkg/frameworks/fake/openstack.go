@@ -0,0 +1,454 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+// Package fake provides an in-memory implementation of the OpenStack operations for tests.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+	"github.com/sapcc/kube-fip-controller/pkg/frameworks"
+)
+
+// OpenStack is an in-memory fake of Nova and Neutron.
+// Floating IPs are reused, tagged and listed by the cluster name and projects of the options like by frameworks.OSFramework.
+type OpenStack struct {
+	mtx         sync.Mutex
+	opts        config.Options
+	servers     map[string]*servers.Server
+	networks    map[string]string
+	subnets     map[string]string
+	ports       map[string]*ports.Port
+	floatingIPs map[string]*neutronfip.FloatingIP
+	reserved    map[string]string
+	createdSeq  map[string]int
+	lastID      int
+	calls       map[string]int
+}
+
+var _ frameworks.OpenStack = &OpenStack{}
+
+// NewOpenStack returns an empty fake using the cluster name and projects of the given options.
+func NewOpenStack(opts config.Options) *OpenStack {
+	return &OpenStack{
+		opts:        opts,
+		servers:     make(map[string]*servers.Server),
+		networks:    make(map[string]string),
+		subnets:     make(map[string]string),
+		ports:       make(map[string]*ports.Port),
+		floatingIPs: make(map[string]*neutronfip.FloatingIP),
+		reserved:    make(map[string]string),
+		createdSeq:  make(map[string]int),
+		calls:       make(map[string]int),
+	}
+}
+
+// AddServer adds an active server with a port using the given fixed IP.
+func (o *OpenStack) AddServer(id, name, projectID, fixedIP string) *servers.Server {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	server := &servers.Server{ID: id, Name: name, TenantID: projectID, Status: "ACTIVE"}
+	o.servers[id] = server
+	portID := o.newID("port")
+	o.ports[portID] = &ports.Port{
+		ID:        portID,
+		DeviceID:  id,
+		ProjectID: projectID,
+		FixedIPs:  []ports.IP{{IPAddress: fixedIP}},
+	}
+	return server
+}
+
+// AddNetwork adds a network and returns its ID.
+func (o *OpenStack) AddNetwork(name string) string {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	id := o.newID("network")
+	o.networks[name] = id
+	return id
+}
+
+// AddSubnet adds a subnet and returns its ID.
+func (o *OpenStack) AddSubnet(name string) string {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	id := o.newID("subnet")
+	o.subnets[name] = id
+	return id
+}
+
+// AddFloatingIP adds a floating IP associated with the server with the given ID or an unassociated one if empty.
+func (o *OpenStack) AddFloatingIP(floatingIP, projectID, description, serverID string) *neutronfip.FloatingIP {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	fip := &neutronfip.FloatingIP{
+		ID:          o.newID("fip"),
+		FloatingIP:  floatingIP,
+		ProjectID:   projectID,
+		Description: description,
+		Status:      "DOWN",
+	}
+	o.addFIP(fip)
+	if serverID != "" {
+		if port := o.getPortOfServer(serverID); port != nil {
+			o.associate(fip, port)
+		}
+	}
+	return copyFIP(fip)
+}
+
+// UpdateFloatingIP replaces the floating IP with the same ID, e.g. to set tags or timestamps.
+func (o *OpenStack) UpdateFloatingIP(fip *neutronfip.FloatingIP) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.floatingIPs[fip.ID] = copyFIP(fip)
+}
+
+// FloatingIPs returns all floating IPs sorted by address.
+func (o *OpenStack) FloatingIPs() []neutronfip.FloatingIP {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	result := make([]neutronfip.FloatingIP, 0, len(o.floatingIPs))
+	for _, fip := range o.floatingIPs {
+		result = append(result, *fip)
+	}
+	slices.SortFunc(result, func(a, b neutronfip.FloatingIP) int { return strings.Compare(a.FloatingIP, b.FloatingIP) })
+	return result
+}
+
+// Reload replaces the options.
+func (o *OpenStack) Reload(_ context.Context, opts config.Options) error {
+	o.count("Reload")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.opts = opts
+	return nil
+}
+
+// ValidateToken always succeeds.
+func (o *OpenStack) ValidateToken(_ context.Context) error {
+	o.count("ValidateToken")
+	return nil
+}
+
+// RefreshSnapshot does nothing as the fake holds all resources in memory anyway.
+func (o *OpenStack) RefreshSnapshot(_ context.Context) error {
+	o.count("RefreshSnapshot")
+	return nil
+}
+
+// GetServerByName returns the server with the given name.
+func (o *OpenStack) GetServerByName(_ context.Context, name string) (*servers.Server, error) {
+	o.count("GetServerByName")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	for _, s := range o.servers {
+		if s.Name == name {
+			server := *s
+			return &server, nil
+		}
+	}
+	return nil, &frameworks.NotFoundError{Resource: "server", Name: name}
+}
+
+// GetServerByID returns the server with the given ID or a 404 error like Nova.
+func (o *OpenStack) GetServerByID(_ context.Context, id string) (*servers.Server, error) {
+	o.count("GetServerByID")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	s, ok := o.servers[id]
+	if !ok {
+		return nil, gophercloud.ErrUnexpectedResponseCode{
+			Method:   http.MethodGet,
+			URL:      "/servers/" + id,
+			Expected: []int{http.StatusOK},
+			Actual:   http.StatusNotFound,
+		}
+	}
+	server := *s
+	return &server, nil
+}
+
+// GetNetworkIDByName returns the ID of the network with the given name.
+func (o *OpenStack) GetNetworkIDByName(_ context.Context, name string) (string, error) {
+	o.count("GetNetworkIDByName")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if id, ok := o.networks[name]; ok {
+		return id, nil
+	}
+	return "", &frameworks.NotFoundError{Resource: "network", Name: name}
+}
+
+// GetSubnetIDByName returns the ID of the subnet with the given name.
+func (o *OpenStack) GetSubnetIDByName(_ context.Context, name string) (string, error) {
+	o.count("GetSubnetIDByName")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if id, ok := o.subnets[name]; ok {
+		return id, nil
+	}
+	return "", &frameworks.NotFoundError{Resource: "subnet", Name: name}
+}
+
+// GetOrCreateFloatingIP behaves like OSFramework.GetOrCreateFloatingIP.
+func (o *OpenStack) GetOrCreateFloatingIP(_ context.Context, floatingIP, floatingNetworkID, _, projectID, nodepool, serverID string, reuse bool) (*neutronfip.FloatingIP, bool, error) {
+	o.count("GetOrCreateFloatingIP")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	for _, fip := range o.sortedFloatingIPs() {
+		if projectID != "" && fip.ProjectID != projectID {
+			continue
+		}
+		if floatingIP != "" && fip.FloatingIP == floatingIP {
+			o.ensureClusterTag(fip)
+			return copyFIP(fip), false, nil
+		}
+		if reuse && floatingIP == "" && nodepool != "" && o.isReusable(fip, nodepool) {
+			if owner, ok := o.reserved[fip.ID]; ok && owner != serverID {
+				continue
+			}
+			o.reserved[fip.ID] = serverID
+			return copyFIP(fip), false, nil
+		}
+	}
+
+	o.calls["CreateFloatingIP"]++
+	if floatingIP == "" {
+		floatingIP = fmt.Sprintf("192.0.2.%d", len(o.floatingIPs)+1)
+	}
+	fip := &neutronfip.FloatingIP{
+		ID:                o.newID("fip"),
+		FloatingNetworkID: floatingNetworkID,
+		FloatingIP:        floatingIP,
+		ProjectID:         projectID,
+		Description:       frameworks.FloatingIPDescription(nodepool),
+		Status:            "DOWN",
+	}
+	o.ensureClusterTag(fip)
+	o.addFIP(fip)
+	return copyFIP(fip), true, nil
+}
+
+// EnsureAssociatedInstanceAndFIP behaves like OSFramework.EnsureAssociatedInstanceAndFIP.
+func (o *OpenStack) EnsureAssociatedInstanceAndFIP(_ context.Context, server *servers.Server, fip *neutronfip.FloatingIP) (*neutronfip.FloatingIP, bool, error) {
+	o.count("EnsureAssociatedInstanceAndFIP")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	stored, ok := o.floatingIPs[fip.ID]
+	if !ok {
+		return nil, false, frameworks.ErrFIPNotFound
+	}
+
+	if stored.PortID != "" {
+		deviceID := o.ports[stored.PortID].DeviceID
+		if deviceID == server.ID {
+			delete(o.reserved, stored.ID)
+			return copyFIP(stored), false, nil
+		}
+		return nil, false, &frameworks.FIPAssociatedElsewhereError{FloatingIP: stored.FloatingIP, ServerID: deviceID}
+	}
+
+	port := o.getPortOfServer(server.ID)
+	if port == nil {
+		return nil, false, fmt.Errorf("no port found for server %s", server.ID)
+	}
+	o.calls["AssociateFloatingIP"]++
+	o.associate(stored, port)
+	delete(o.reserved, stored.ID)
+	return copyFIP(stored), true, nil
+}
+
+// GetFloatingIPByAddress returns the floating IP with the given address or frameworks.ErrFIPNotFound.
+func (o *OpenStack) GetFloatingIPByAddress(_ context.Context, floatingIP string) (*neutronfip.FloatingIP, error) {
+	o.count("GetFloatingIPByAddress")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	for _, fip := range o.floatingIPs {
+		if floatingIP != "" && fip.FloatingIP == floatingIP {
+			return copyFIP(fip), nil
+		}
+	}
+	return nil, frameworks.ErrFIPNotFound
+}
+
+// DisassociateFloatingIP disassociates the floating IP from its port.
+func (o *OpenStack) DisassociateFloatingIP(_ context.Context, fip *neutronfip.FloatingIP) error {
+	o.count("DisassociateFloatingIP")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if stored, ok := o.floatingIPs[fip.ID]; ok {
+		stored.PortID = ""
+		stored.FixedIP = ""
+		stored.Status = "DOWN"
+	}
+	return nil
+}
+
+// DeleteFloatingIP deletes floating IPs allocated by the controller and disassociates all others.
+func (o *OpenStack) DeleteFloatingIP(ctx context.Context, fip *neutronfip.FloatingIP) error {
+	if !frameworks.IsCreatedByController(fip) {
+		return o.DisassociateFloatingIP(ctx, fip)
+	}
+
+	o.count("DeleteFloatingIP")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	delete(o.floatingIPs, fip.ID)
+	return nil
+}
+
+// IsAssociatedWithOtherServer checks whether the floating IP is associated with a server other than the given one.
+func (o *OpenStack) IsAssociatedWithOtherServer(ctx context.Context, fip *neutronfip.FloatingIP, serverID string) (bool, error) {
+	associatedServerID, err := o.GetAssociatedServerID(ctx, fip)
+	if err != nil {
+		return false, err
+	}
+	return associatedServerID != "" && associatedServerID != serverID, nil
+}
+
+// GetAssociatedServerID returns the ID of the server the floating IP is associated with or an empty string.
+func (o *OpenStack) GetAssociatedServerID(_ context.Context, fip *neutronfip.FloatingIP) (string, error) {
+	o.count("GetAssociatedServerID")
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if fip.PortID == "" {
+		return "", nil
+	}
+	port, ok := o.ports[fip.PortID]
+	if !ok {
+		return "", fmt.Errorf("port %s not found", fip.PortID)
+	}
+	return port.DeviceID, nil
+}
+
+// ListFloatingIPs returns all floating IPs.
+func (o *OpenStack) ListFloatingIPs(_ context.Context) ([]neutronfip.FloatingIP, error) {
+	o.count("ListFloatingIPs")
+	return o.FloatingIPs(), nil
+}
+
+// ListFloatingIPsCreatedByController returns the floating IPs allocated by the controller of the cluster in the configured projects.
+func (o *OpenStack) ListFloatingIPsCreatedByController(ctx context.Context) ([]neutronfip.FloatingIP, error) {
+	o.mtx.Lock()
+	clusterName, projectIDs := o.opts.ClusterName, o.opts.ProjectIDs
+	o.mtx.Unlock()
+	if clusterName == "" || len(projectIDs) == 0 {
+		return nil, errors.New("listing the floating IPs of the cluster requires --cluster-name and --project-id")
+	}
+
+	allFIPs, err := o.ListFloatingIPs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(allFIPs, func(fip neutronfip.FloatingIP) bool {
+		return !slices.Contains(projectIDs, fip.ProjectID) || !frameworks.IsCreatedByController(&fip) || !frameworks.HasClusterTag(&fip, clusterName)
+	}), nil
+}
+
+func (o *OpenStack) count(operation string) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.calls[operation]++
+}
+
+// Calls returns how often the operation was called. Besides the methods, "CreateFloatingIP" and "AssociateFloatingIP" are counted.
+func (o *OpenStack) Calls(operation string) int {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.calls[operation]
+}
+
+func (o *OpenStack) newID(prefix string) string {
+	o.lastID++
+	return fmt.Sprintf("%s-%d", prefix, o.lastID)
+}
+
+func (o *OpenStack) getPortOfServer(serverID string) *ports.Port {
+	for _, port := range o.ports {
+		if port.DeviceID == serverID {
+			return port
+		}
+	}
+	return nil
+}
+
+func (o *OpenStack) associate(fip *neutronfip.FloatingIP, port *ports.Port) {
+	fip.PortID = port.ID
+	fip.FixedIP = port.FixedIPs[0].IPAddress
+	fip.Status = "ACTIVE"
+}
+
+func (o *OpenStack) addFIP(fip *neutronfip.FloatingIP) {
+	o.floatingIPs[fip.ID] = fip
+	o.createdSeq[fip.ID] = o.lastID
+}
+
+// sortedFloatingIPs returns the floating IPs in the order they were created.
+func (o *OpenStack) sortedFloatingIPs() []*neutronfip.FloatingIP {
+	result := make([]*neutronfip.FloatingIP, 0, len(o.floatingIPs))
+	for _, fip := range o.floatingIPs {
+		result = append(result, fip)
+	}
+	slices.SortFunc(result, func(a, b *neutronfip.FloatingIP) int { return o.createdSeq[a.ID] - o.createdSeq[b.ID] })
+	return result
+}
+
+// isReusable behaves like the check of frameworks.OSFramework.
+func (o *OpenStack) isReusable(fip *neutronfip.FloatingIP, nodepool string) bool {
+	return fip.FixedIP == "" && fip.Description == frameworks.FloatingIPDescription(nodepool) &&
+		(o.opts.ClusterName == "" || frameworks.HasClusterTag(fip, o.opts.ClusterName))
+}
+
+// ensureClusterTag tags floating IPs allocated by the controller with the cluster name like frameworks.OSFramework.
+func (o *OpenStack) ensureClusterTag(fip *neutronfip.FloatingIP) {
+	if o.opts.ClusterName == "" || !frameworks.IsCreatedByController(fip) || frameworks.HasClusterTag(fip, o.opts.ClusterName) {
+		return
+	}
+	fip.Tags = append(fip.Tags, frameworks.ClusterTag(o.opts.ClusterName))
+}
+
+func copyFIP(fip *neutronfip.FloatingIP) *neutronfip.FloatingIP {
+	c := *fip
+	return &c
+}
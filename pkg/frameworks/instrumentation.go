@@ -0,0 +1,74 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+const (
+	serviceCompute  = "compute"
+	serviceNetwork  = "network"
+	serviceIdentity = "identity"
+
+	labelUnknown = "unknown"
+)
+
+type operationContextKey struct{}
+
+type operation struct {
+	service, name string
+}
+
+// withOperation returns a context labelling the requests made with it for the metrics.
+func withOperation(ctx context.Context, service, name string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation{service: service, name: name})
+}
+
+func getOperation(ctx context.Context) operation {
+	if op, ok := ctx.Value(operationContextKey{}).(operation); ok {
+		return op
+	}
+	// Requests without an operation are made by gophercloud itself, e.g. for authentication.
+	return operation{service: labelUnknown, name: labelUnknown}
+}
+
+// instrumentedRoundTripper observes the duration and status of every request to the OpenStack APIs.
+type instrumentedRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *instrumentedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	op := getOperation(req.Context())
+	start := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.MetricOpenStackRequestDuration.WithLabelValues(op.service, op.name, status).Observe(time.Since(start).Seconds())
+	return resp, err
+}
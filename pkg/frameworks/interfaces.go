@@ -0,0 +1,56 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+)
+
+// OpenStack are the operations on Nova and Neutron used by the controller.
+type OpenStack interface {
+	// Reload replaces the clients using the given options.
+	Reload(ctx context.Context, opts config.Options) error
+	// ValidateToken checks whether the current token is still valid.
+	ValidateToken(ctx context.Context) error
+	// RefreshSnapshot lists all floating IPs and ports at once for the next reconciliation cycle.
+	RefreshSnapshot(ctx context.Context) error
+
+	GetServerByName(ctx context.Context, name string) (*servers.Server, error)
+	GetServerByID(ctx context.Context, id string) (*servers.Server, error)
+	GetNetworkIDByName(ctx context.Context, name string) (string, error)
+	GetSubnetIDByName(ctx context.Context, name string) (string, error)
+
+	GetOrCreateFloatingIP(ctx context.Context, floatingIP, floatingNetworkID, subnetID, projectID, nodepool, serverID string, reuse bool) (*neutronfip.FloatingIP, bool, error)
+	EnsureAssociatedInstanceAndFIP(ctx context.Context, server *servers.Server, fip *neutronfip.FloatingIP) (*neutronfip.FloatingIP, bool, error)
+	GetFloatingIPByAddress(ctx context.Context, floatingIP string) (*neutronfip.FloatingIP, error)
+	DisassociateFloatingIP(ctx context.Context, fip *neutronfip.FloatingIP) error
+	DeleteFloatingIP(ctx context.Context, fip *neutronfip.FloatingIP) error
+	IsAssociatedWithOtherServer(ctx context.Context, fip *neutronfip.FloatingIP, serverID string) (bool, error)
+	GetAssociatedServerID(ctx context.Context, fip *neutronfip.FloatingIP) (string, error)
+	ListFloatingIPs(ctx context.Context) ([]neutronfip.FloatingIP, error)
+	ListFloatingIPsCreatedByController(ctx context.Context) ([]neutronfip.FloatingIP, error)
+}
+
+var _ OpenStack = &OSFramework{}
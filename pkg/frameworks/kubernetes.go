@@ -21,34 +21,59 @@ package frameworks
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	apimachinerywatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	informersv1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/retry"
 
+	"github.com/sapcc/kube-fip-controller/pkg/apis/v1alpha1"
 	"github.com/sapcc/kube-fip-controller/pkg/config"
 )
 
 const (
 	resyncPeriod = 5 * time.Minute
 	waitTimeout  = 2 * time.Minute
+
+	eventComponent = "kube-fip-controller"
 )
 
 // K8sFramework ..
 type K8sFramework struct {
-	*kubernetes.Clientset
-	nodeInformer cache.SharedIndexInformer
-	logger       log.Logger
+	kubernetes.Interface
+	dynamicClient dynamic.Interface
+	nodeInformer  cache.SharedIndexInformer
+	poolInformer  cache.SharedIndexInformer
+	claimInformer cache.SharedIndexInformer
+	// recorder is nil until StartEventRecording is called.
+	recorder record.EventRecorder
+	logger   log.Logger
+	dryRun   bool
+	// requestTimeout bounds requests to the API server. Informers and leader election are not affected.
+	requestTimeout time.Duration
 }
 
 // NewK8sFramework returns a new K8sFramework or an error.
@@ -69,11 +94,36 @@ func NewK8sFramework(options config.Options, logger log.Logger) (*K8sFramework,
 		return nil, err
 	}
 
-	return &K8sFramework{
-		Clientset:    clientSet,
-		logger:       log.With(logger, "component", "k8sFramework"),
-		nodeInformer: informersv1.NewNodeInformer(clientSet, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
-	}, nil
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewK8sFrameworkForClients(options, clientSet, dynamicClient, logger), nil
+}
+
+// NewK8sFrameworkForClients returns a new K8sFramework using the given clients.
+func NewK8sFrameworkForClients(options config.Options, clientSet kubernetes.Interface, dynamicClient dynamic.Interface, logger log.Logger) *K8sFramework {
+	k8s := &K8sFramework{
+		Interface:      clientSet,
+		dynamicClient:  dynamicClient,
+		logger:         log.With(logger, "component", "k8sFramework"),
+		nodeInformer:   informersv1.NewNodeInformer(clientSet, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+		dryRun:         options.DryRun,
+		requestTimeout: options.KubernetesTimeout,
+	}
+
+	if options.EnableFloatingIPPools {
+		k8s.poolInformer = dynamicinformer.NewFilteredDynamicInformer(
+			dynamicClient, v1alpha1.FloatingIPPoolResource, metav1.NamespaceAll, resyncPeriod, cache.Indexers{}, nil,
+		).Informer()
+	}
+	if options.EnableFloatingIPClaims {
+		k8s.claimInformer = dynamicinformer.NewFilteredDynamicInformer(
+			dynamicClient, v1alpha1.FloatingIPClaimResource, metav1.NamespaceAll, resyncPeriod, cache.Indexers{}, nil,
+		).Informer()
+	}
+	return k8s
 }
 
 // AddEventHandlerFuncsToNodeInformer adds EventHandlerFuncs to the node informer.
@@ -89,21 +139,84 @@ func (k8s *K8sFramework) AddEventHandlerFuncsToNodeInformer(addFunc, deleteFunc
 	}
 }
 
+// AddEventHandlerFuncsToFloatingIPPoolInformer adds EventHandlerFuncs to the FloatingIPPool informer if pools are enabled.
+func (k8s *K8sFramework) AddEventHandlerFuncsToFloatingIPPoolInformer(handlerFunc func(obj interface{})) {
+	if k8s.poolInformer == nil {
+		return
+	}
+
+	_, err := k8s.poolInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: handlerFunc,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Status updates do not change the generation and do not affect the nodes of the pool.
+			oldMeta, err := meta.Accessor(oldObj)
+			if err != nil {
+				return
+			}
+			newMeta, err := meta.Accessor(newObj)
+			if err != nil {
+				return
+			}
+			if oldMeta.GetGeneration() != newMeta.GetGeneration() {
+				handlerFunc(newObj)
+			}
+		},
+		DeleteFunc: handlerFunc,
+	})
+	if err != nil {
+		//nolint:errcheck
+		_ = k8s.logger.Log("msg", "failed to add event handlers to floating ip pool informer", "err", err)
+	}
+}
+
 // Run starts the frameworks informers.
 func (k8s *K8sFramework) Run(stopCh <-chan struct{}) {
 	go k8s.nodeInformer.Run(stopCh)
+	if k8s.poolInformer != nil {
+		go k8s.poolInformer.Run(stopCh)
+	}
+	if k8s.claimInformer != nil {
+		go k8s.claimInformer.Run(stopCh)
+	}
+}
+
+// StartEventRecording starts sending recorded events to the API server. Events recorded before are dropped.
+func (k8s *K8sFramework) StartEventRecording() {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8s.CoreV1().Events(metav1.NamespaceAll)})
+	k8s.recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: eventComponent})
 }
 
 // WaitForCacheToSync waits until all informer caches have been synced.
 func (k8s *K8sFramework) WaitForCacheToSync(stopCh <-chan struct{}) bool {
-	return cache.WaitForCacheSync(
-		stopCh,
-		k8s.nodeInformer.HasSynced,
-	)
+	return cache.WaitForCacheSync(stopCh, k8s.getInformerSyncs()...)
+}
+
+// HasSynced checks whether all informer caches have been synced.
+func (k8s *K8sFramework) HasSynced() bool {
+	for _, hasSynced := range k8s.getInformerSyncs() {
+		if !hasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (k8s *K8sFramework) getInformerSyncs() []cache.InformerSynced {
+	cacheSyncs := []cache.InformerSynced{k8s.nodeInformer.HasSynced}
+	if k8s.poolInformer != nil {
+		cacheSyncs = append(cacheSyncs, k8s.poolInformer.HasSynced)
+	}
+	if k8s.claimInformer != nil {
+		cacheSyncs = append(cacheSyncs, k8s.claimInformer.HasSynced)
+	}
+	return cacheSyncs
 }
 
 // GetNode gets a node by name and returns it or an error.
 func (k8s *K8sFramework) GetNode(ctx context.Context, name string) (*corev1.Node, error) {
+	ctx, cancel := k8s.withRequestTimeout(ctx)
+	defer cancel()
 	return k8s.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 }
 
@@ -113,6 +226,11 @@ func (k8s *K8sFramework) AddLabelsToNode(ctx context.Context, node *corev1.Node,
 		return nil
 	}
 
+	if k8s.dryRun {
+		logDryRun(k8s.logger, dryRunActionLabelNode, "would label node", "node", node.GetName(), "labels", fmt.Sprintf("%v", labels))
+		return nil
+	}
+
 	oldNode, err := k8s.GetNode(ctx, node.GetName())
 	if err != nil {
 		return err
@@ -129,12 +247,148 @@ func (k8s *K8sFramework) AddLabelsToNode(ctx context.Context, node *corev1.Node,
 	}
 	newNode.SetLabels(existingLabels)
 
-	updatedNode, err := k8s.CoreV1().Nodes().Update(ctx, newNode, metav1.UpdateOptions{})
+	updateCtx, cancel := k8s.withRequestTimeout(ctx)
+	defer cancel()
+	updatedNode, err := k8s.CoreV1().Nodes().Update(updateCtx, newNode, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	return k8s.waitForNode(ctx, updatedNode, []watch.ConditionFunc{hasNodeLabels(node.GetName(), labels)}...)
+}
+
+// AddFinalizerToNode adds the finalizer to the node if it is not present yet.
+func (k8s *K8sFramework) AddFinalizerToNode(ctx context.Context, node *corev1.Node, finalizer string) error {
+	return k8s.updateNodeFinalizers(ctx, node, func(finalizers []string) []string {
+		if slices.Contains(finalizers, finalizer) {
+			return finalizers
+		}
+		return append(finalizers, finalizer)
+	})
+}
+
+// RemoveFinalizerFromNode removes the finalizer from the node if it is present.
+func (k8s *K8sFramework) RemoveFinalizerFromNode(ctx context.Context, node *corev1.Node, finalizer string) error {
+	return k8s.updateNodeFinalizers(ctx, node, func(finalizers []string) []string {
+		return slices.DeleteFunc(finalizers, func(f string) bool { return f == finalizer })
+	})
+}
+
+func (k8s *K8sFramework) updateNodeFinalizers(ctx context.Context, node *corev1.Node, mutateFunc func(finalizers []string) []string) error {
+	oldNode, err := k8s.GetNode(ctx, node.GetName())
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
 
-	return k8s.waitForNode(updatedNode, []watch.ConditionFunc{isNodeModified}...)
+	newNode := oldNode.DeepCopy()
+	finalizers := mutateFunc(slices.Clone(newNode.GetFinalizers()))
+	if slices.Equal(finalizers, oldNode.GetFinalizers()) {
+		return nil
+	}
+	newNode.SetFinalizers(finalizers)
+
+	if k8s.dryRun {
+		logDryRun(k8s.logger, dryRunActionUpdateNodeFinalizers, "would update finalizers of node", "node", node.GetName(), "finalizers", strings.Join(finalizers, ","))
+		return nil
+	}
+
+	ctx, cancel := k8s.withRequestTimeout(ctx)
+	defer cancel()
+	_, err = k8s.CoreV1().Nodes().Update(ctx, newNode, metav1.UpdateOptions{})
+	return err
+}
+
+// SetNodeCondition sets the condition in the node's status if its status, reason or message changed.
+func (k8s *K8sFramework) SetNodeCondition(ctx context.Context, node *corev1.Node, condition corev1.NodeCondition) error {
+	// The given node is usually the informer's copy. The node is only fetched if the condition needs to be updated.
+	if hasNodeCondition(node, condition) {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		oldNode, err := k8s.GetNode(ctx, node.GetName())
+		if err != nil {
+			return err
+		}
+
+		now := metav1.Now()
+		condition.LastHeartbeatTime = now
+		condition.LastTransitionTime = now
+
+		newNode := oldNode.DeepCopy()
+		idx := slices.IndexFunc(newNode.Status.Conditions, func(c corev1.NodeCondition) bool { return c.Type == condition.Type })
+		if idx < 0 {
+			newNode.Status.Conditions = append(newNode.Status.Conditions, condition)
+		} else {
+			existing := newNode.Status.Conditions[idx]
+			if isSameNodeCondition(existing, condition) {
+				return nil
+			}
+			if existing.Status == condition.Status {
+				condition.LastTransitionTime = existing.LastTransitionTime
+			}
+			newNode.Status.Conditions[idx] = condition
+		}
+
+		if k8s.dryRun {
+			logDryRun(k8s.logger, dryRunActionSetNodeCondition, "would set condition of node",
+				"node", node.GetName(), "type", condition.Type, "status", condition.Status, "reason", condition.Reason,
+			)
+			return nil
+		}
+
+		updateCtx, cancel := k8s.withRequestTimeout(ctx)
+		defer cancel()
+		_, err = k8s.CoreV1().Nodes().UpdateStatus(updateCtx, newNode, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// hasNodeCondition checks whether the node has the condition with the same status, reason and message.
+func hasNodeCondition(node *corev1.Node, condition corev1.NodeCondition) bool {
+	idx := slices.IndexFunc(node.Status.Conditions, func(c corev1.NodeCondition) bool { return c.Type == condition.Type })
+	return idx >= 0 && isSameNodeCondition(node.Status.Conditions[idx], condition)
+}
+
+func isSameNodeCondition(a, b corev1.NodeCondition) bool {
+	return a.Status == b.Status && a.Reason == b.Reason && a.Message == b.Message
+}
+
+// NewLeaseLock returns a lease based lock for leader election.
+func (k8s *K8sFramework) NewLeaseLock(name, namespace, identity string) resourcelock.Interface {
+	return &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Client: k8s.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+}
+
+// RecordNodeEvent records an event for the given node.
+func (k8s *K8sFramework) RecordNodeEvent(node *corev1.Node, eventType, reason, messageFmt string, args ...interface{}) {
+	// Events of nodes are referenced by name instead of UID like the kubelet does, so they show up in kubectl describe node.
+	ref := &corev1.ObjectReference{
+		Kind: "Node",
+		Name: node.GetName(),
+		UID:  types.UID(node.GetName()),
+	}
+	if k8s.dryRun {
+		logDryRun(k8s.logger, dryRunActionRecordEvent, "would record event", "node", node.GetName(), "type", eventType, "reason", reason,
+			"message", fmt.Sprintf(messageFmt, args...),
+		)
+		return
+	}
+	if k8s.recorder == nil {
+		return
+	}
+	k8s.recorder.Eventf(ref, eventType, reason, messageFmt, args...)
 }
 
 // GetNodeFromIndexerByKey returns a node by key from the informer's indexer.
@@ -151,17 +405,149 @@ func (k8s *K8sFramework) GetNodeInformerStore() cache.Store {
 	return k8s.nodeInformer.GetStore()
 }
 
-func (k8s *K8sFramework) waitForNode(node *corev1.Node, conditionFuncs ...watch.ConditionFunc) error {
-	ctx, cancel := context.WithTimeout(context.TODO(), waitTimeout)
+// ListFloatingIPPools returns all FloatingIPPools from the informer's store.
+func (k8s *K8sFramework) ListFloatingIPPools() ([]*v1alpha1.FloatingIPPool, error) {
+	if k8s.poolInformer == nil {
+		return nil, nil
+	}
+
+	objs := k8s.poolInformer.GetStore().List()
+	pools := make([]*v1alpha1.FloatingIPPool, 0, len(objs))
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		pool := &v1alpha1.FloatingIPPool{}
+		if err := fromUnstructured(u, pool); err != nil {
+			return nil, err
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// UpdateFloatingIPPoolStatus updates the status of the given FloatingIPPool.
+func (k8s *K8sFramework) UpdateFloatingIPPoolStatus(ctx context.Context, pool *v1alpha1.FloatingIPPool) error {
+	if k8s.dryRun {
+		logDryRun(k8s.logger, dryRunActionUpdatePoolStatus, "would update status of FloatingIPPool", "pool", pool.GetName())
+		return nil
+	}
+
+	u, err := toUnstructured(pool, "FloatingIPPool")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := k8s.withRequestTimeout(ctx)
+	defer cancel()
+	_, err = k8s.dynamicClient.Resource(v1alpha1.FloatingIPPoolResource).UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	return err
+}
+
+// GetOrCreateFloatingIPClaim returns the FloatingIPClaim of the node from the informer's store and creates it if it does not exist yet.
+func (k8s *K8sFramework) GetOrCreateFloatingIPClaim(ctx context.Context, node *corev1.Node) (*v1alpha1.FloatingIPClaim, error) {
+	if k8s.claimInformer == nil {
+		return nil, errors.New("floating ip claims are not enabled")
+	}
+
+	obj, exists, err := k8s.claimInformer.GetStore().GetByKey(node.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if u, ok := obj.(*unstructured.Unstructured); exists && ok {
+		claim := &v1alpha1.FloatingIPClaim{}
+		return claim, fromUnstructured(u, claim)
+	}
+
+	ctx, cancel := k8s.withRequestTimeout(ctx)
+	defer cancel()
+
+	client := k8s.dynamicClient.Resource(v1alpha1.FloatingIPClaimResource)
+	claim := &v1alpha1.FloatingIPClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: node.GetName(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(node, corev1.SchemeGroupVersion.WithKind("Node")),
+			},
+		},
+		Spec: v1alpha1.FloatingIPClaimSpec{
+			NodeName: node.GetName(),
+		},
+	}
+	if k8s.dryRun {
+		logDryRun(k8s.logger, dryRunActionCreateClaim, "would create FloatingIPClaim", "claim", claim.GetName())
+		return claim, nil
+	}
+
+	u, err := toUnstructured(claim, "FloatingIPClaim")
+	if err != nil {
+		return nil, err
+	}
+
+	u, err = client.Create(ctx, u, metav1.CreateOptions{})
+	// The informer might not have seen a claim created by a previous sync yet.
+	if apierrors.IsAlreadyExists(err) {
+		u, err = client.Get(ctx, node.GetName(), metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return claim, fromUnstructured(u, claim)
+}
+
+// UpdateFloatingIPClaimStatus updates the status of the given FloatingIPClaim.
+func (k8s *K8sFramework) UpdateFloatingIPClaimStatus(ctx context.Context, claim *v1alpha1.FloatingIPClaim) error {
+	if k8s.dryRun {
+		logDryRun(k8s.logger, dryRunActionUpdateClaimStatus, "would update status of FloatingIPClaim", "claim", claim.GetName(), "fip", claim.Status.FloatingIP)
+		return nil
+	}
+
+	u, err := toUnstructured(claim, "FloatingIPClaim")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := k8s.withRequestTimeout(ctx)
+	defer cancel()
+	_, err = k8s.dynamicClient.Resource(v1alpha1.FloatingIPClaimResource).UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	return err
+}
+
+func toUnstructured(obj interface{}, kind string) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(kind))
+	return u, nil
+}
+
+func fromUnstructured(u *unstructured.Unstructured, obj interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj)
+}
+
+// withRequestTimeout returns a context bounded by the request timeout if one is configured.
+func (k8s *K8sFramework) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if k8s.requestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, k8s.requestTimeout)
+}
+
+func (k8s *K8sFramework) waitForNode(ctx context.Context, node *corev1.Node, conditionFuncs ...watch.ConditionFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
 	defer cancel()
 	_, err := watch.UntilWithSync(
 		ctx,
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (object runtime.Object, e error) {
-				return k8s.CoreV1().Nodes().List(context.Background(), metav1.SingleObject(metav1.ObjectMeta{Name: node.GetName()}))
+				return k8s.CoreV1().Nodes().List(ctx, metav1.SingleObject(metav1.ObjectMeta{Name: node.GetName()}))
 			},
 			WatchFunc: func(options metav1.ListOptions) (i apimachinerywatch.Interface, e error) {
-				return k8s.CoreV1().Nodes().Watch(context.Background(), metav1.SingleObject(metav1.ObjectMeta{Name: node.GetName()}))
+				return k8s.CoreV1().Nodes().Watch(ctx, metav1.SingleObject(metav1.ObjectMeta{Name: node.GetName()}))
 			},
 		},
 		node,
@@ -171,13 +557,26 @@ func (k8s *K8sFramework) waitForNode(node *corev1.Node, conditionFuncs ...watch.
 	return err
 }
 
-func isNodeModified(event apimachinerywatch.Event) (bool, error) {
-	switch event.Type {
-	case apimachinerywatch.Deleted:
-		return false, apierrors.NewNotFound(schema.GroupResource{Resource: "node"}, "")
-	case apimachinerywatch.Modified:
-		return true, nil
-	default:
-		return false, nil
+// hasNodeLabels returns a condition that is met once the node carries all the given labels.
+func hasNodeLabels(name string, labels map[string]string) watch.ConditionFunc {
+	return func(event apimachinerywatch.Event) (bool, error) {
+		node, ok := event.Object.(*corev1.Node)
+		if !ok || node.GetName() != name {
+			return false, nil
+		}
+
+		switch event.Type {
+		case apimachinerywatch.Deleted:
+			return false, apierrors.NewNotFound(schema.GroupResource{Resource: "node"}, name)
+		case apimachinerywatch.Added, apimachinerywatch.Modified:
+			for k, v := range labels {
+				if node.GetLabels()[k] != v {
+					return false, nil
+				}
+			}
+			return true, nil
+		default:
+			return false, nil
+		}
 	}
 }
@@ -0,0 +1,97 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+)
+
+func TestSetNodeConditionSkipsUnchangedCondition(t *testing.T) {
+	condition := corev1.NodeCondition{Type: "FloatingIPReady", Status: corev1.ConditionTrue, Reason: "Associated", Message: "ok"}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{condition}},
+	}
+	kube := kubefake.NewSimpleClientset(node)
+	k8s := NewK8sFrameworkForClients(config.Options{}, kube, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), log.NewNopLogger())
+	kube.ClearActions()
+
+	if err := k8s.SetNodeCondition(context.Background(), node, condition); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, action := range kube.Actions() {
+		if action.GetResource().Resource == "nodes" {
+			t.Errorf("expected no request for an unchanged condition, got %s", action.GetVerb())
+		}
+	}
+
+	condition.Status = corev1.ConditionFalse
+	if err := k8s.SetNodeCondition(context.Background(), node, condition); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated, err := kube.CoreV1().Nodes().Get(context.Background(), "node-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	if got := updated.Status.Conditions[0].Status; got != corev1.ConditionFalse {
+		t.Errorf("expected condition status %s, got %s", corev1.ConditionFalse, got)
+	}
+}
+
+func TestRecordNodeEventRequiresEventRecording(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	kube := kubefake.NewSimpleClientset(node)
+	k8s := NewK8sFrameworkForClients(config.Options{}, kube, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), log.NewNopLogger())
+	if k8s.recorder != nil {
+		t.Fatal("expected events not to be recorded before the recording was started")
+	}
+	k8s.RecordNodeEvent(node, corev1.EventTypeNormal, "Dropped", "dropped")
+
+	k8s.StartEventRecording()
+	k8s.RecordNodeEvent(node, corev1.EventTypeNormal, "Recorded", "recorded")
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, time.Second, true, func(ctx context.Context) (bool, error) {
+		events, err := kube.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, event := range events.Items {
+			if event.Reason == "Dropped" {
+				return false, errors.New("expected the event recorded before the start to be dropped")
+			}
+		}
+		return len(events.Items) == 1, nil
+	})
+	if err != nil {
+		t.Fatalf("expected the event to be recorded: %v", err)
+	}
+}
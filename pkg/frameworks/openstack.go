@@ -21,7 +21,14 @@ package frameworks
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -29,6 +36,7 @@ import (
 	"github.com/gophercloud/gophercloud/v2/openstack"
 	"github.com/gophercloud/gophercloud/v2/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/attributestags"
 	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
@@ -43,26 +51,99 @@ const (
 	statusActive                 = "ACTIVE"
 	createFIPDescription         = "Floating IP allocated by kube-fip-controller"
 	createFIPDescriptionNodepool = "Floating IP allocated by kube-fip-controller nodepool=%s"
+	clusterTagPrefix             = "kube-fip-controller-cluster="
+	tokenValidationInterval      = 30 * time.Second
 )
 
 var allProjectsHeader = map[string]string{"X-Auth-All-Projects": "true"}
 
 // OSFramework is the OpenStack Framework.
 type OSFramework struct {
-	computeClient,
-	neutronClient *gophercloud.ServiceClient
-	logger log.Logger
-	opts   config.Options
+	clients atomic.Pointer[serviceClients]
+	logger  log.Logger
+	opts    config.Options
+
+	tokenValidationMtx  sync.Mutex
+	lastTokenValidation time.Time
+
+	networkIDCache    *ttlCache[string]
+	subnetIDCache     *ttlCache[string]
+	serverByIDCache   *ttlCache[*servers.Server]
+	serverByNameCache *ttlCache[*servers.Server]
+
+	// snapshot is only set if --bulk-reconciliation is enabled.
+	snapshot     atomic.Pointer[snapshot]
+	reservations *reservations
+
+	limiters rateLimiters
+}
+
+// serviceClients are replaced as a whole when the credentials are reloaded.
+type serviceClients struct {
+	compute,
+	neutron,
+	identity *gophercloud.ServiceClient
 }
 
 // NewOSFramework returns a new OSFramework.
 func NewOSFramework(ctx context.Context, opts config.Options, logger log.Logger) (*OSFramework, error) {
-	provider, err := newAuthenticatedProviderClient(ctx, opts.Auth)
+	limiters := newRateLimiters(opts)
+	clients, err := newServiceClients(ctx, opts, limiters)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &OSFramework{
+		limiters:          limiters,
+		logger:            log.With(logger, "component", "osFramework"),
+		opts:              opts,
+		networkIDCache:    newTTLCache[string](cacheNetworkIDs),
+		subnetIDCache:     newTTLCache[string](cacheSubnetIDs),
+		serverByIDCache:   newTTLCache[*servers.Server](cacheServerByID),
+		serverByNameCache: newTTLCache[*servers.Server](cacheServerByName),
+		reservations:      newReservations(),
+	}
+	o.clients.Store(clients)
+	return o, nil
+}
+
+// Reload authenticates with the credentials of the given options and replaces the service clients.
+// Requests in flight finish with the previous clients.
+func (o *OSFramework) Reload(ctx context.Context, opts config.Options) error {
+	clients, err := newServiceClients(ctx, opts, o.limiters)
+	if err != nil {
+		return err
+	}
+
+	o.clients.Store(clients)
+	o.tokenValidationMtx.Lock()
+	o.lastTokenValidation = time.Time{}
+	o.tokenValidationMtx.Unlock()
+	// The new credentials might grant access to different resources.
+	o.purgeCaches()
+	o.snapshot.Store(nil)
+	//nolint:errcheck
+	_ = level.Info(o.logger).Log("msg", "reloaded OpenStack credentials")
+	return nil
+}
+
+func newServiceClients(ctx context.Context, opts config.Options, limiters rateLimiters) (*serviceClients, error) {
+	var (
+		provider     *gophercloud.ProviderClient
+		endpointOpts gophercloud.EndpointOpts
+		err          error
+	)
+	if opts.Cloud != nil {
+		provider, err = newAuthenticatedProviderClientFromCloud(ctx, opts.Cloud, opts.OpenStackTimeout, limiters)
+		endpointOpts = opts.Cloud.EndpointOpts
+	} else {
+		provider, err = newAuthenticatedProviderClient(ctx, opts.Auth, opts.OpenStackTimeout, limiters)
+		endpointOpts = opts.Auth.EndpointOpts()
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to authenticate")
 	}
 
-	endpointOpts := gophercloud.EndpointOpts{}
 	cClient, err := openstack.NewComputeV2(provider, endpointOpts)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create compute v2 client")
@@ -73,44 +154,196 @@ func NewOSFramework(ctx context.Context, opts config.Options, logger log.Logger)
 		return nil, errors.Wrap(err, "failed to create network v2 client")
 	}
 
-	return &OSFramework{
-		computeClient: cClient,
-		neutronClient: nClient,
-		logger:        log.With(logger, "component", "osFramework"),
-		opts:          opts,
+	iClient, err := openstack.NewIdentityV3(provider, endpointOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create identity v3 client")
+	}
+
+	return &serviceClients{
+		compute:  cClient,
+		neutron:  nClient,
+		identity: iClient,
 	}, nil
 }
 
-func newAuthenticatedProviderClient(ctx context.Context, auth *config.Auth) (*gophercloud.ProviderClient, error) {
-	opts := &tokens.AuthOptions{
-		IdentityEndpoint: auth.AuthURL,
-		Username:         auth.Username,
-		Password:         auth.Password,
-		DomainName:       auth.UserDomainName,
-		AllowReauth:      true,
-		Scope: tokens.Scope{
-			ProjectName: auth.ProjectName,
-			DomainName:  auth.ProjectDomainName,
-		},
+// ValidateToken checks whether the current token is valid.
+// Successful validations are cached for the tokenValidationInterval to limit the requests to Keystone.
+func (o *OSFramework) ValidateToken(ctx context.Context) error {
+	o.tokenValidationMtx.Lock()
+	defer o.tokenValidationMtx.Unlock()
+	if time.Since(o.lastTokenValidation) < tokenValidationInterval {
+		return nil
 	}
 
-	provider, err := openstack.NewClient(auth.AuthURL)
+	client := o.clients.Load().identity
+	ok, err := tokens.Validate(withOperation(ctx, serviceIdentity, "validate_token"), client, client.Token())
+	if err != nil {
+		return errors.Wrap(err, "failed to validate token")
+	}
+	if !ok {
+		return errors.New("token is not valid")
+	}
+
+	o.lastTokenValidation = time.Now()
+	return nil
+}
+
+func (o *OSFramework) computeClient() *gophercloud.ServiceClient {
+	return o.clients.Load().compute
+}
+
+func (o *OSFramework) neutronClient() *gophercloud.ServiceClient {
+	return o.clients.Load().neutron
+}
+
+func newAuthenticatedProviderClient(ctx context.Context, auth *config.Auth, timeout time.Duration, limiters rateLimiters) (*gophercloud.ProviderClient, error) {
+	tlsConfig, err := auth.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := newProviderClient(auth.AuthURL, tlsConfig, timeout, limiters)
 	if err != nil {
 		return nil, err
 	}
 
-	err = openstack.AuthenticateV3(ctx, provider, opts, gophercloud.EndpointOpts{})
+	err = openstack.AuthenticateV3(ctx, provider, newAuthOptions(auth), gophercloud.EndpointOpts{})
 	return provider, err
 }
 
-// GetServerByName returns an openstack server found by name or an error.
+func newAuthenticatedProviderClientFromCloud(ctx context.Context, cloud *config.Cloud, timeout time.Duration, limiters rateLimiters) (*gophercloud.ProviderClient, error) {
+	provider, err := newProviderClient(cloud.AuthOptions.IdentityEndpoint, cloud.TLSConfig, timeout, limiters)
+	if err != nil {
+		return nil, err
+	}
+
+	err = openstack.Authenticate(ctx, provider, cloud.AuthOptions)
+	return provider, err
+}
+
+// newProviderClient returns an unauthenticated provider client using the given TLS configuration if not nil.
+// Requests are limited per service and throttled requests are retried after a backoff.
+// The timeout applies to every single request including the time spent waiting for the rate limiter. 0 disables it.
+func newProviderClient(authURL string, tlsConfig *tls.Config, timeout time.Duration, limiters rateLimiters) (*gophercloud.ProviderClient, error) {
+	provider, err := openstack.NewClient(authURL)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:errcheck
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	provider.HTTPClient.Transport = &rateLimitedRoundTripper{
+		next:     &instrumentedRoundTripper{next: transport},
+		limiters: limiters,
+	}
+	provider.HTTPClient.Timeout = timeout
+	provider.RetryBackoffFunc = limiters.retryBackoff
+	provider.MaxBackoffRetries = maxBackoffRetries
+	return provider, nil
+}
+
+func newAuthOptions(auth *config.Auth) *tokens.AuthOptions {
+	opts := &tokens.AuthOptions{
+		IdentityEndpoint: auth.AuthURL,
+		AllowReauth:      true,
+	}
+
+	switch auth.AuthType {
+	case config.AuthTypeApplicationCredential:
+		// Application credentials are already scoped.
+		opts.ApplicationCredentialID = auth.ApplicationCredentialID
+		opts.ApplicationCredentialName = auth.ApplicationCredentialName
+		opts.ApplicationCredentialSecret = auth.ApplicationCredentialSecret
+		opts.Username = auth.Username
+		opts.DomainName = auth.UserDomainName
+		return opts
+	case config.AuthTypeToken:
+		// A token cannot be used to re-authenticate once it expired.
+		opts.TokenID = auth.Token
+		opts.AllowReauth = false
+	default:
+		opts.Username = auth.Username
+		opts.Password = auth.Password
+		opts.DomainName = auth.UserDomainName
+	}
+
+	if auth.TrustID != "" {
+		opts.Scope = tokens.Scope{
+			TrustID: auth.TrustID,
+		}
+	} else {
+		opts.Scope = tokens.Scope{
+			ProjectName: auth.ProjectName,
+			DomainName:  auth.ProjectDomainName,
+		}
+	}
+	return opts
+}
+
+// GetServerByName returns an openstack server found by name or an error. Servers are cached for the --server-cache-ttl.
 func (o *OSFramework) GetServerByName(ctx context.Context, name string) (*servers.Server, error) {
+	return o.serverByNameCache.getOrLoad(name, o.opts.ServerCacheTTL, o.opts.NegativeCacheTTL, func() (*servers.Server, error) {
+		return o.getServerByName(ctx, name)
+	})
+}
+
+// GetServerByID returns the server or an error. Servers are cached for the --server-cache-ttl.
+func (o *OSFramework) GetServerByID(ctx context.Context, id string) (*servers.Server, error) {
+	server, err := o.serverByIDCache.getOrLoad(id, o.opts.ServerCacheTTL, o.opts.NegativeCacheTTL, func() (*servers.Server, error) {
+		return o.getServerByID(ctx, id)
+	})
+	if is404(err) {
+		o.serverByNameCache.deleteFunc(func(_ string, server *servers.Server) bool {
+			return server != nil && server.ID == id
+		})
+	}
+	return server, err
+}
+
+// GetNetworkIDByName returns the id of the network found by name or an error. IDs are cached for the --network-cache-ttl.
+func (o *OSFramework) GetNetworkIDByName(ctx context.Context, name string) (string, error) {
+	return o.networkIDCache.getOrLoad(name, o.opts.NetworkCacheTTL, o.opts.NegativeCacheTTL, func() (string, error) {
+		return o.getNetworkIDByName(ctx, name)
+	})
+}
+
+// GetSubnetIDByName returns the subnet's id for the given name or an error. IDs are cached for the --network-cache-ttl.
+func (o *OSFramework) GetSubnetIDByName(ctx context.Context, name string) (string, error) {
+	return o.subnetIDCache.getOrLoad(name, o.opts.NetworkCacheTTL, o.opts.NegativeCacheTTL, func() (string, error) {
+		return o.getSubnetIDByName(ctx, name)
+	})
+}
+
+func (o *OSFramework) purgeCaches() {
+	o.networkIDCache.purge()
+	o.subnetIDCache.purge()
+	o.serverByIDCache.purge()
+	o.serverByNameCache.purge()
+}
+
+// invalidateServer removes the server from the caches, e.g. if it was deleted.
+func (o *OSFramework) invalidateServer(id string) {
+	o.serverByIDCache.delete(id)
+	o.serverByNameCache.deleteFunc(func(_ string, server *servers.Server) bool {
+		return server != nil && server.ID == id
+	})
+}
+
+// invalidateNetworks removes all networks and subnets from the caches, e.g. if one of them was deleted.
+func (o *OSFramework) invalidateNetworks() {
+	o.networkIDCache.purge()
+	o.subnetIDCache.purge()
+}
+
+func (o *OSFramework) getServerByName(ctx context.Context, name string) (*servers.Server, error) {
 	listOpts := servers.ListOpts{
 		Name:       name,
 		AllTenants: true,
 	}
 
-	allPages, err := servers.List(o.computeClient, listOpts).AllPages(ctx)
+	allPages, err := servers.List(o.computeClient(), listOpts).AllPages(withOperation(ctx, serviceCompute, "list_servers"))
 	if err != nil {
 		return nil, err
 	}
@@ -125,17 +358,15 @@ func (o *OSFramework) GetServerByName(ctx context.Context, name string) (*server
 			return &s, nil
 		}
 	}
-	return nil, fmt.Errorf("no server with name %s found", name)
+	return nil, &NotFoundError{Resource: "server", Name: name}
 }
 
-// GetServerByID returns the server or an error.
-func (o *OSFramework) GetServerByID(ctx context.Context, id string) (*servers.Server, error) {
-	return servers.Get(ctx, o.computeClient, id).Extract()
+func (o *OSFramework) getServerByID(ctx context.Context, id string) (*servers.Server, error) {
+	return servers.Get(withOperation(ctx, serviceCompute, "get_server"), o.computeClient(), id).Extract()
 }
 
-// GetNetworkIDByName returns the id of the network found by name or an error.
-func (o *OSFramework) GetNetworkIDByName(ctx context.Context, name string) (string, error) {
-	url := o.neutronClient.ServiceURL("networks")
+func (o *OSFramework) getNetworkIDByName(ctx context.Context, name string) (string, error) {
+	url := o.neutronClient().ServiceURL("networks")
 	listOpts := networks.ListOpts{
 		Name:   name,
 		Status: statusActive,
@@ -158,7 +389,7 @@ func (o *OSFramework) GetNetworkIDByName(ctx context.Context, name string) (stri
 		MoreHeaders: allProjectsHeader,
 	}
 
-	_, res.Err = o.neutronClient.Get(ctx, url, &res.Body, &opts)
+	_, res.Err = o.neutronClient().Get(withOperation(ctx, serviceNetwork, "list_networks"), url, &res.Body, &opts)
 	if err := res.ExtractInto(&resData); err != nil {
 		return "", err
 	}
@@ -169,16 +400,15 @@ func (o *OSFramework) GetNetworkIDByName(ctx context.Context, name string) (stri
 		}
 	}
 
-	return "", fmt.Errorf("no network with name %s found", name)
+	return "", &NotFoundError{Resource: "network", Name: name}
 }
 
-// GetSubnetIDByName returns the subnet's id for the given name or an error.
-func (o *OSFramework) GetSubnetIDByName(ctx context.Context, name string) (string, error) {
+func (o *OSFramework) getSubnetIDByName(ctx context.Context, name string) (string, error) {
 	listOpts := subnets.ListOpts{
 		Name: name,
 	}
 
-	allPages, err := subnets.List(o.neutronClient, listOpts).AllPages(ctx)
+	allPages, err := subnets.List(o.neutronClient(), listOpts).AllPages(withOperation(ctx, serviceNetwork, "list_subnets"))
 	if err != nil {
 		return "", err
 	}
@@ -194,69 +424,341 @@ func (o *OSFramework) GetSubnetIDByName(ctx context.Context, name string) (strin
 		}
 	}
 
-	return "", fmt.Errorf("no subnet with name %s found", name)
+	return "", &NotFoundError{Resource: "subnet", Name: name}
 }
 
 // GetOrCreateFloatingIP gets and existing or create a new neutron floating IP and returns it or an error.
-func (o *OSFramework) GetOrCreateFloatingIP(ctx context.Context, floatingIP, floatingNetworkID, subnetID, projectID, nodepool string, reuse bool) (*neutronfip.FloatingIP, error) {
-	fip, err := o.getFloatingIP(ctx, floatingIP, projectID, nodepool, reuse)
+// The returned bool indicates whether the floating IP was created.
+// A reused floating IP is reserved for the server until it is associated with it.
+func (o *OSFramework) GetOrCreateFloatingIP(ctx context.Context, floatingIP, floatingNetworkID, subnetID, projectID, nodepool, serverID string, reuse bool) (*neutronfip.FloatingIP, bool, error) {
+	var (
+		fip *neutronfip.FloatingIP
+		err error
+	)
+	if reuse && floatingIP == "" && nodepool != "" {
+		fip, err = o.getReusableFloatingIP(ctx, projectID, nodepool, serverID)
+	} else {
+		fip, err = o.getFloatingIP(ctx, floatingIP, projectID)
+	}
 	if err == nil {
-		return fip, nil
+		if err := o.ensureClusterTag(ctx, fip); err != nil {
+			//nolint:errcheck
+			_ = level.Error(o.logger).Log("msg", "error tagging FIP", "fip", fip.FloatingIP, "id", fip.ID, "err", err)
+		}
+		return fip, false, nil
 	}
 
 	if IsFIPNotFound(err) {
-		return o.createFloatingIP(ctx, floatingIP, floatingNetworkID, subnetID, projectID, nodepool)
+		fip, err := o.createFloatingIP(ctx, floatingIP, floatingNetworkID, subnetID, projectID, nodepool)
+		return fip, err == nil, err
 	}
 
-	return nil, err
+	return nil, false, err
 }
 
 // EnsureAssociatedInstanceAndFIP ensures the given floating IP is associated with the given server.
-func (o *OSFramework) EnsureAssociatedInstanceAndFIP(ctx context.Context, server *servers.Server, fip *neutronfip.FloatingIP) error {
+// It returns the associated floating IP and whether it was associated by this call.
+func (o *OSFramework) EnsureAssociatedInstanceAndFIP(ctx context.Context, server *servers.Server, fip *neutronfip.FloatingIP) (*neutronfip.FloatingIP, bool, error) {
+	// The placeholder of a floating IP, which would be created in dry run mode, has neither an ID nor a port.
+	if o.opts.DryRun && fip.ID == "" {
+		fip, err := o.associateInstanceAndFIP(ctx, server, fip)
+		return fip, err == nil, err
+	}
+
+	// Unassociated floating IPs taken from the snapshot might have been associated since it was listed, so they are read again before associating them.
+	// Associated ones are verified via their port.
+	if o.opts.BulkReconciliation && fip.PortID == "" {
+		current, err := o.getFloatingIPByID(ctx, fip.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		fip = current
+	}
+
+	// An unassociated floating IP has no port to verify. Getting the port with an empty ID would list all ports instead.
+	if fip.PortID == "" {
+		return o.associateReservedInstanceAndFIP(ctx, server, fip)
+	}
+
 	// Get the floating IPs port.
 	port, err := o.getPortByID(ctx, fip.PortID)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	switch port.DeviceID {
 	case "":
-		return o.associateInstanceAndFIP(ctx, server, fip.FloatingIP)
+		return o.associateReservedInstanceAndFIP(ctx, server, fip)
 	case server.ID:
+		o.reservations.release(fip.ID, server.ID)
 		// If the port belongs to the server, we can assume the FIP is already associated with the server and return here.
 		//nolint:errcheck
 		_ = level.Info(o.logger).Log("msg", "FIP already attached to instance", "fip", fip.FloatingIP, "serverID", server.ID)
-		return nil
+		return fip, false, nil
 	default:
-		return fmt.Errorf("FIP already associated with another server %s", server.Name)
+		return nil, false, &FIPAssociatedElsewhereError{FloatingIP: fip.FloatingIP, ServerID: port.DeviceID}
+	}
+}
+
+// associateReservedInstanceAndFIP associates the floating IP with the server and releases its reservation.
+// On failures the reservation is kept, so the floating IP is not reused for another server before the next attempt.
+func (o *OSFramework) associateReservedInstanceAndFIP(ctx context.Context, server *servers.Server, fip *neutronfip.FloatingIP) (*neutronfip.FloatingIP, bool, error) {
+	fipID := fip.ID
+	fip, err := o.associateInstanceAndFIP(ctx, server, fip)
+	if err != nil {
+		return nil, false, err
 	}
+	o.reservations.release(fipID, server.ID)
+	return fip, true, nil
+}
+
+// GetFloatingIPByAddress returns the neutron floating IP with the given address or ErrFIPNotFound.
+func (o *OSFramework) GetFloatingIPByAddress(ctx context.Context, floatingIP string) (*neutronfip.FloatingIP, error) {
+	return o.getFloatingIP(ctx, floatingIP, "")
 }
 
-func (o *OSFramework) associateInstanceAndFIP(ctx context.Context, server *servers.Server, floatingIP string) error {
+// DisassociateFloatingIP disassociates the given floating IP from its port if it is associated.
+func (o *OSFramework) DisassociateFloatingIP(ctx context.Context, fip *neutronfip.FloatingIP) error {
+	if fip.PortID == "" {
+		return nil
+	}
+
+	if o.opts.DryRun {
+		logDryRun(o.logger, dryRunActionDisassociateFIP, "would disassociate FIP", "fip", fip.FloatingIP, "id", fip.ID, "portID", fip.PortID)
+		return nil
+	}
+
+	portID := ""
 	opts := neutronfip.UpdateOpts{
-		FixedIP: floatingIP,
+		PortID: &portID,
 	}
 	//nolint:errcheck
-	_ = level.Info(o.logger).Log("msg", "attaching FIP to instance", "fip", floatingIP, "serverID", server.ID)
-	_, err := neutronfip.Update(ctx, o.neutronClient, server.ID, opts).Extract()
+	_ = level.Info(o.logger).Log("msg", "disassociating FIP", "fip", fip.FloatingIP, "id", fip.ID, "portID", fip.PortID)
+	updated, err := neutronfip.Update(withOperation(ctx, serviceNetwork, "update_floatingip"), o.neutronClient(), fip.ID, opts).Extract()
 	if err != nil {
 		//nolint:errcheck
-		_ = level.Error(o.logger).Log("msg", "error attaching FIP to instance", "fip", floatingIP, "serverID", server.ID, "err", err)
-		metrics.MetricErrorAssociateInstanceAndFIP.Inc()
+		_ = level.Error(o.logger).Log("msg", "error disassociating FIP", "fip", fip.FloatingIP, "id", fip.ID, "err", err)
+		metrics.MetricErrorDisassociateFIP.Inc()
 		return err
 	}
+	if s := o.snapshot.Load(); s != nil {
+		s.updateFloatingIP(updated)
+	}
 	return nil
 }
 
+// DeleteFloatingIP deletes the given floating IP if it was allocated by the controller.
+// Floating IPs allocated by someone else are only disassociated.
+func (o *OSFramework) DeleteFloatingIP(ctx context.Context, fip *neutronfip.FloatingIP) error {
+	if !IsCreatedByController(fip) {
+		//nolint:errcheck
+		_ = level.Info(o.logger).Log("msg", "FIP was not allocated by the controller. only disassociating it", "fip", fip.FloatingIP, "id", fip.ID)
+		return o.DisassociateFloatingIP(ctx, fip)
+	}
+
+	if o.opts.DryRun {
+		logDryRun(o.logger, dryRunActionDeleteFIP, "would delete FIP", "fip", fip.FloatingIP, "id", fip.ID)
+		return nil
+	}
+
+	//nolint:errcheck
+	_ = level.Info(o.logger).Log("msg", "deleting FIP", "fip", fip.FloatingIP, "id", fip.ID)
+	err := neutronfip.Delete(withOperation(ctx, serviceNetwork, "delete_floatingip"), o.neutronClient(), fip.ID).ExtractErr()
+	if err != nil {
+		//nolint:errcheck
+		_ = level.Error(o.logger).Log("msg", "error deleting FIP", "fip", fip.FloatingIP, "id", fip.ID, "err", err)
+		metrics.MetricErrorDeleteFIP.Inc()
+		return err
+	}
+	if s := o.snapshot.Load(); s != nil {
+		s.deleteFloatingIP(fip)
+	}
+	return nil
+}
+
+// IsAssociatedWithOtherServer checks whether the given floating IP is associated with a server other than the given one.
+func (o *OSFramework) IsAssociatedWithOtherServer(ctx context.Context, fip *neutronfip.FloatingIP, serverID string) (bool, error) {
+	associatedServerID, err := o.GetAssociatedServerID(ctx, fip)
+	if err != nil {
+		return false, err
+	}
+	return associatedServerID != "" && associatedServerID != serverID, nil
+}
+
+// GetAssociatedServerID returns the ID of the server the given floating IP is associated with or an empty string.
+func (o *OSFramework) GetAssociatedServerID(ctx context.Context, fip *neutronfip.FloatingIP) (string, error) {
+	if fip.PortID == "" {
+		return "", nil
+	}
+
+	port, err := o.getPortByID(ctx, fip.PortID)
+	if err != nil {
+		return "", err
+	}
+	return port.DeviceID, nil
+}
+
+// ListFloatingIPs returns all floating IPs visible to the controller.
+func (o *OSFramework) ListFloatingIPs(ctx context.Context) ([]neutronfip.FloatingIP, error) {
+	return o.listFloatingIPs(ctx, neutronfip.ListOpts{})
+}
+
+// ListFloatingIPsCreatedByController returns the floating IPs allocated by the controller of this cluster in the configured projects.
+// Floating IPs of other clusters using the same credentials are not returned, as they are tagged with another cluster name.
+func (o *OSFramework) ListFloatingIPsCreatedByController(ctx context.Context) ([]neutronfip.FloatingIP, error) {
+	if o.opts.ClusterName == "" || len(o.opts.ProjectIDs) == 0 {
+		return nil, errors.New("listing the floating IPs of the cluster requires --cluster-name and --project-id")
+	}
+
+	result := make([]neutronfip.FloatingIP, 0)
+	for _, projectID := range o.opts.ProjectIDs {
+		fips, err := o.listFloatingIPs(ctx, neutronfip.ListOpts{ProjectID: projectID, Tags: ClusterTag(o.opts.ClusterName)})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list floating ips of project %s", projectID)
+		}
+		for _, fip := range fips {
+			if IsCreatedByController(&fip) && HasClusterTag(&fip, o.opts.ClusterName) {
+				result = append(result, fip)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (o *OSFramework) listFloatingIPs(ctx context.Context, listOpts neutronfip.ListOpts) ([]neutronfip.FloatingIP, error) {
+	allPages, err := neutronfip.List(o.neutronClient(), listOpts).AllPages(withOperation(ctx, serviceNetwork, "list_floatingips"))
+	if err != nil {
+		return nil, err
+	}
+	return neutronfip.ExtractFloatingIPs(allPages)
+}
+
+// GetNodepoolOfFloatingIP returns the nodepool the floating IP was allocated for or an empty string.
+func GetNodepoolOfFloatingIP(fip *neutronfip.FloatingIP) string {
+	nodepool, ok := strings.CutPrefix(fip.Description, strings.TrimSuffix(createFIPDescriptionNodepool, "%s"))
+	if !ok {
+		return ""
+	}
+	return nodepool
+}
+
+// FloatingIPDescription returns the description of floating IPs allocated by the controller for the given nodepool.
+func FloatingIPDescription(nodepool string) string {
+	if nodepool == "" {
+		return createFIPDescription
+	}
+	return fmt.Sprintf(createFIPDescriptionNodepool, nodepool)
+}
+
+// IsCreatedByController checks whether the given floating IP was allocated by the controller.
+func IsCreatedByController(fip *neutronfip.FloatingIP) bool {
+	return fip.Description == createFIPDescription ||
+		strings.HasPrefix(fip.Description, strings.TrimSuffix(createFIPDescriptionNodepool, "%s"))
+}
+
+// ClusterTag returns the tag of floating IPs allocated by the controller of the given cluster.
+func ClusterTag(clusterName string) string {
+	return clusterTagPrefix + clusterName
+}
+
+// HasClusterTag checks whether the given floating IP is tagged with the given cluster.
+func HasClusterTag(fip *neutronfip.FloatingIP, clusterName string) bool {
+	return clusterName != "" && slices.Contains(fip.Tags, ClusterTag(clusterName))
+}
+
+// ensureClusterTag tags a floating IP allocated by the controller with the cluster name if it is configured.
+// Floating IPs allocated before the cluster name was configured are tagged once they are used by a node of the cluster.
+func (o *OSFramework) ensureClusterTag(ctx context.Context, fip *neutronfip.FloatingIP) error {
+	if o.opts.ClusterName == "" || !IsCreatedByController(fip) || HasClusterTag(fip, o.opts.ClusterName) {
+		return nil
+	}
+
+	tag := ClusterTag(o.opts.ClusterName)
+	if o.opts.DryRun {
+		logDryRun(o.logger, dryRunActionTagFIP, "would tag FIP", "fip", fip.FloatingIP, "id", fip.ID, "tag", tag)
+		return nil
+	}
+
+	err := attributestags.Add(withOperation(ctx, serviceNetwork, "add_tag"), o.neutronClient(), "floatingips", fip.ID, tag).ExtractErr()
+	if err != nil {
+		return err
+	}
+	fip.Tags = append(fip.Tags, tag)
+	if s := o.snapshot.Load(); s != nil {
+		s.updateFloatingIP(fip)
+	}
+	return nil
+}
+
+// isReusable checks whether an unassociated floating IP may be used for a node of the nodepool.
+// If the cluster name is configured, only floating IPs of this cluster are reused.
+func (o *OSFramework) isReusable(fip *neutronfip.FloatingIP, nodepool string) bool {
+	return fip.FixedIP == "" && fip.Description == FloatingIPDescription(nodepool) &&
+		(o.opts.ClusterName == "" || HasClusterTag(fip, o.opts.ClusterName))
+}
+
+func (o *OSFramework) associateInstanceAndFIP(ctx context.Context, server *servers.Server, fip *neutronfip.FloatingIP) (*neutronfip.FloatingIP, error) {
+	floatingIP := fip.FloatingIP
+	if o.opts.DryRun {
+		logDryRun(o.logger, dryRunActionAssociateFIP, "would associate FIP with instance", "fip", floatingIP, "id", fip.ID, "serverID", server.ID)
+		return fip, nil
+	}
+
+	port, err := o.getPortOfServer(ctx, server.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := neutronfip.UpdateOpts{
+		PortID: &port.ID,
+	}
+	//nolint:errcheck
+	_ = level.Info(o.logger).Log("msg", "attaching FIP to instance", "fip", floatingIP, "serverID", server.ID, "portID", port.ID)
+	fip, err = neutronfip.Update(withOperation(ctx, serviceNetwork, "update_floatingip"), o.neutronClient(), fip.ID, opts).Extract()
+	if err != nil {
+		//nolint:errcheck
+		_ = level.Error(o.logger).Log("msg", "error attaching FIP to instance", "fip", floatingIP, "serverID", server.ID, "err", err)
+		metrics.MetricErrorAssociateInstanceAndFIP.Inc()
+		if is404(err) {
+			o.invalidateServer(server.ID)
+		}
+		return nil, err
+	}
+	if s := o.snapshot.Load(); s != nil {
+		s.updateFloatingIP(fip)
+	}
+	return fip, nil
+}
+
 func (o *OSFramework) getPortByID(ctx context.Context, id string) (*ports.Port, error) {
-	return ports.Get(ctx, o.neutronClient, id).Extract()
+	if s := o.getSnapshot(); s != nil {
+		if port, ok := s.getPort(id); ok {
+			return port, nil
+		}
+	}
+	return ports.Get(withOperation(ctx, serviceNetwork, "get_port"), o.neutronClient(), id).Extract()
 }
 
-func (o *OSFramework) createFloatingIP(ctx context.Context, floatingIP, floatingNetworkID, subnetID, projectID, nodepool string) (*neutronfip.FloatingIP, error) {
-	description := createFIPDescription
-	if nodepool != "" {
-		description = fmt.Sprintf(createFIPDescriptionNodepool, nodepool)
+// getPortOfServer returns the first port of the server, which the floating IP is associated with.
+func (o *OSFramework) getPortOfServer(ctx context.Context, serverID string) (*ports.Port, error) {
+	if s := o.getSnapshot(); s != nil {
+		if port, ok := s.getPortOfDevice(serverID); ok {
+			return port, nil
+		}
+	}
+
+	serverPorts, err := o.listPorts(ctx, ports.ListOpts{DeviceID: serverID})
+	if err != nil {
+		return nil, err
+	}
+	if len(serverPorts) == 0 {
+		return nil, errors.Errorf("no port of server %s found", serverID)
 	}
+	return &serverPorts[0], nil
+}
+
+func (o *OSFramework) createFloatingIP(ctx context.Context, floatingIP, floatingNetworkID, subnetID, projectID, nodepool string) (*neutronfip.FloatingIP, error) {
+	description := FloatingIPDescription(nodepool)
 
 	createOpts := neutronfip.CreateOpts{
 		FloatingNetworkID: floatingNetworkID,
@@ -265,44 +767,119 @@ func (o *OSFramework) createFloatingIP(ctx context.Context, floatingIP, floating
 		ProjectID:         projectID,
 		Description:       description,
 	}
-	fip, err := neutronfip.Create(ctx, o.neutronClient, createOpts).Extract()
+	if o.opts.DryRun {
+		logDryRun(o.logger, dryRunActionCreateFIP, "would create floating ip",
+			"floatingIP", floatingIP, "floatingNetworkID", floatingNetworkID, "subnetID", subnetID, "projectID", projectID, "description", description,
+		)
+		// The placeholder has no ID and is not associated with any port.
+		placeholder := &neutronfip.FloatingIP{
+			FloatingNetworkID: floatingNetworkID,
+			FloatingIP:        floatingIP,
+			ProjectID:         projectID,
+			Description:       description,
+		}
+		if o.opts.ClusterName != "" {
+			placeholder.Tags = []string{ClusterTag(o.opts.ClusterName)}
+		}
+		return placeholder, nil
+	}
+
+	fip, err := neutronfip.Create(withOperation(ctx, serviceNetwork, "create_floatingip"), o.neutronClient(), createOpts).Extract()
 	if err != nil {
 		//nolint:errcheck
 		_ = level.Error(o.logger).Log("msg", "error creating floating ip", "floatingIP", floatingIP, "err", err)
 		metrics.MetricErrorCreateFIP.Inc()
+		if is404(err) {
+			o.invalidateNetworks()
+		}
 		return nil, err
 	}
 	//nolint:errcheck
 	_ = level.Info(o.logger).Log("msg", "created floating ip", "floatingIP", fip.FloatingIP, "id", fip.ID)
+	// Untagged floating IPs are never collected, so a failure is not fatal. It is tagged again with the next sync.
+	if err := o.ensureClusterTag(ctx, fip); err != nil {
+		//nolint:errcheck
+		_ = level.Error(o.logger).Log("msg", "error tagging floating ip", "floatingIP", fip.FloatingIP, "id", fip.ID, "err", err)
+	}
+	if s := o.snapshot.Load(); s != nil {
+		s.updateFloatingIP(fip)
+	}
 	return fip, nil
 }
 
-func (o *OSFramework) getFloatingIP(ctx context.Context, floatingIP, projectID, nodepool string, reuse bool) (*neutronfip.FloatingIP, error) {
-	listOpts := neutronfip.ListOpts{
-		FloatingIP: floatingIP,
-		ProjectID:  projectID,
+// getFloatingIP returns the floating IP with the given address.
+// Floating IPs unknown to the snapshot might have been created since, so they are looked up via the API.
+func (o *OSFramework) getFloatingIP(ctx context.Context, floatingIP, projectID string) (*neutronfip.FloatingIP, error) {
+	if floatingIP == "" {
+		return nil, ErrFIPNotFound
 	}
-	if reuse && floatingIP == "" && nodepool != "" {
-		listOpts.Description = fmt.Sprintf(createFIPDescriptionNodepool, nodepool)
-	}
-	allPages, err := neutronfip.List(o.neutronClient, listOpts).AllPages(ctx)
-	if err != nil {
-		return nil, err
+	if s := o.getSnapshot(); s != nil {
+		if fip, ok := s.getFloatingIP(floatingIP, projectID); ok {
+			return fip, nil
+		}
 	}
 
-	allFIPs, err := neutronfip.ExtractFloatingIPs(allPages)
+	allFIPs, err := o.listFloatingIPs(ctx, neutronfip.ListOpts{FloatingIP: floatingIP, ProjectID: projectID})
 	if err != nil {
 		return nil, err
 	}
-
 	for _, fip := range allFIPs {
 		if fip.FloatingIP == floatingIP {
 			return &fip, nil
 		}
-		if reuse && floatingIP == "" && nodepool != "" && fip.FixedIP == "" {
-			return &fip, nil
+	}
+	return nil, ErrFIPNotFound
+}
+
+// getReusableFloatingIP returns an unassociated floating IP of the nodepool and reserves it for the server.
+// The snapshot only narrows down the candidates. They are read again, as they might have been associated since it was listed.
+func (o *OSFramework) getReusableFloatingIP(ctx context.Context, projectID, nodepool, serverID string) (*neutronfip.FloatingIP, error) {
+	if s := o.getSnapshot(); s != nil {
+		for _, candidate := range s.getReusableFloatingIPs(projectID, nodepool) {
+			if !o.isReusable(candidate, nodepool) || !o.reservations.reserve(candidate.ID, serverID) {
+				continue
+			}
+			fip, err := o.getFloatingIPByID(ctx, candidate.ID)
+			if err == nil && o.isReusable(fip, nodepool) {
+				return fip, nil
+			}
+			o.reservations.release(candidate.ID, serverID)
+			if err != nil && !IsFIPNotFound(err) {
+				return nil, err
+			}
 		}
 	}
 
+	listOpts := neutronfip.ListOpts{
+		ProjectID:   projectID,
+		Description: FloatingIPDescription(nodepool),
+	}
+	if o.opts.ClusterName != "" {
+		listOpts.Tags = ClusterTag(o.opts.ClusterName)
+	}
+	allFIPs, err := o.listFloatingIPs(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, fip := range allFIPs {
+		if o.isReusable(&fip, nodepool) && o.reservations.reserve(fip.ID, serverID) {
+			return &fip, nil
+		}
+	}
 	return nil, ErrFIPNotFound
 }
+
+// getFloatingIPByID reads the floating IP from the API and updates the snapshot with it.
+func (o *OSFramework) getFloatingIPByID(ctx context.Context, id string) (*neutronfip.FloatingIP, error) {
+	fip, err := neutronfip.Get(withOperation(ctx, serviceNetwork, "get_floatingip"), o.neutronClient(), id).Extract()
+	if err != nil {
+		if is404(err) {
+			return nil, ErrFIPNotFound
+		}
+		return nil, err
+	}
+	if s := o.snapshot.Load(); s != nil {
+		s.updateFloatingIP(fip)
+	}
+	return fip, nil
+}
@@ -0,0 +1,69 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"testing"
+
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+)
+
+func TestIsReusable(t *testing.T) {
+	tests := map[string]struct {
+		clusterName string
+		fip         neutronfip.FloatingIP
+		want        bool
+	}{
+		"untagged without cluster name": {
+			fip:  neutronfip.FloatingIP{Description: FloatingIPDescription("pool-a")},
+			want: true,
+		},
+		"tagged with this cluster": {
+			clusterName: "a",
+			fip:         neutronfip.FloatingIP{Description: FloatingIPDescription("pool-a"), Tags: []string{ClusterTag("a")}},
+			want:        true,
+		},
+		"tagged with another cluster": {
+			clusterName: "a",
+			fip:         neutronfip.FloatingIP{Description: FloatingIPDescription("pool-a"), Tags: []string{ClusterTag("b")}},
+		},
+		"untagged with cluster name": {
+			clusterName: "a",
+			fip:         neutronfip.FloatingIP{Description: FloatingIPDescription("pool-a")},
+		},
+		"other nodepool": {
+			fip: neutronfip.FloatingIP{Description: FloatingIPDescription("pool-b")},
+		},
+		"associated": {
+			fip: neutronfip.FloatingIP{Description: FloatingIPDescription("pool-a"), FixedIP: "10.0.0.1"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &OSFramework{opts: config.Options{ClusterName: tc.clusterName}}
+			if got := o.isReusable(&tc.fip, "pool-a"); got != tc.want {
+				t.Errorf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"golang.org/x/time/rate"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+	"github.com/sapcc/kube-fip-controller/pkg/metrics"
+)
+
+const (
+	// maxBackoffRetries limits how often a throttled request is retried.
+	maxBackoffRetries = 5
+	// defaultBackoff is used if the API does not send a Retry-After header. It is doubled with every retry.
+	defaultBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// serviceLimiter limits the rate and concurrency of the requests to an OpenStack service.
+type serviceLimiter struct {
+	limiter  *rate.Limiter
+	inFlight chan struct{}
+
+	mtx         sync.Mutex
+	pausedUntil time.Time
+}
+
+func newServiceLimiter(limits config.RateLimit) *serviceLimiter {
+	l := &serviceLimiter{}
+	if limits.QPS > 0 {
+		burst := limits.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		l.limiter = rate.NewLimiter(rate.Limit(limits.QPS), burst)
+	}
+	if limits.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+	return l
+}
+
+// acquire blocks until the request may be sent. The returned function must be called once the request is done.
+func (l *serviceLimiter) acquire(ctx context.Context) (func(), error) {
+	l.mtx.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mtx.Unlock()
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if l.limiter != nil {
+		if err := l.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l.inFlight <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-l.inFlight }) }, nil
+	}
+}
+
+// pause delays all requests to the service, e.g. after the API asked to retry later.
+func (l *serviceLimiter) pause(d time.Duration) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// rateLimiters hold the limiters per service. They are kept when the credentials are reloaded.
+type rateLimiters map[string]*serviceLimiter
+
+func newRateLimiters(opts config.Options) rateLimiters {
+	return rateLimiters{
+		serviceCompute: newServiceLimiter(opts.ComputeRateLimit),
+		serviceNetwork: newServiceLimiter(opts.NetworkRateLimit),
+	}
+}
+
+// retryBackoff is called by gophercloud if a request was throttled with 429.
+// It pauses all requests to the service for the duration given by the Retry-After header or an exponential backoff.
+func (r rateLimiters) retryBackoff(ctx context.Context, respErr *gophercloud.ErrUnexpectedResponseCode, err error, retries uint) error {
+	op := getOperation(ctx)
+	metrics.MetricOpenStackThrottledRequests.WithLabelValues(op.service).Inc()
+
+	d, ok := parseRetryAfter(respErr.ResponseHeader.Get("Retry-After"))
+	if !ok {
+		d = defaultBackoff << (retries - 1)
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	if l, ok := r[op.service]; ok {
+		l.pause(d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses the Retry-After header given either in seconds or as HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitedRoundTripper applies the limiter of the service to every request.
+type rateLimitedRoundTripper struct {
+	next     http.RoundTripper
+	limiters rateLimiters
+}
+
+func (rt *rateLimitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	l, ok := rt.limiters[getOperation(req.Context()).service]
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	release, err := l.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// The request is in flight until its body was read.
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,134 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+
+	"github.com/sapcc/kube-fip-controller/pkg/config"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		"empty":       {value: ""},
+		"seconds":     {value: "3", want: 3 * time.Second, wantOK: true},
+		"negative":    {value: "-1"},
+		"invalid":     {value: "soon"},
+		"date passed": {value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, wantOK: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value)
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("expected %s, %t, got %s, %t", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRateLimitedRoundTripperLimitsRequestsInFlight(t *testing.T) {
+	rt := &rateLimitedRoundTripper{
+		next: roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		}),
+		limiters: rateLimiters{serviceNetwork: newServiceLimiter(config.RateLimit{MaxInFlight: 1})},
+	}
+
+	newRequest := func(ctx context.Context) *http.Request {
+		req, err := http.NewRequestWithContext(withOperation(ctx, serviceNetwork, "get_port"), http.MethodGet, "http://neutron/v2.0/ports/1", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	resp, err := rt.RoundTrip(newRequest(context.Background()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The body of the first response was not closed yet, so the second request must wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := rt.RoundTrip(newRequest(ctx)); err == nil {
+		t.Fatal("expected second request to be blocked")
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = rt.RoundTrip(newRequest(context.Background()))
+	if err != nil {
+		t.Fatalf("unexpected error after the first request finished: %v", err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	// Requests to services without limiter are passed through.
+	req, err := http.NewRequest(http.MethodGet, "http://keystone/v3", http.NoBody) //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestServiceLimiterPause(t *testing.T) {
+	l := newServiceLimiter(config.RateLimit{})
+	l.pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Fatal("expected request to wait until the pause is over")
+	}
+}
+
+func TestRetryBackoffReturnsContextError(t *testing.T) {
+	respErr := &gophercloud.ErrUnexpectedResponseCode{
+		Actual:         http.StatusTooManyRequests,
+		ResponseHeader: http.Header{"Retry-After": []string{"60"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newRateLimiters(config.Options{}).retryBackoff(ctx, respErr, nil, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
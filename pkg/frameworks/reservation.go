@@ -0,0 +1,73 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+package frameworks
+
+import (
+	"sync"
+	"time"
+)
+
+// reservationTimeout is the duration after which a reservation expires if the floating IP was not associated,
+// e.g. because labeling the node failed and the node was deleted before the next sync.
+const reservationTimeout = 5 * time.Minute
+
+// reservations hold the reusable floating IPs selected for a server until they are associated with it,
+// so concurrent workers do not select the same floating IP for different servers.
+type reservations struct {
+	mtx     sync.Mutex
+	entries map[string]reservation
+}
+
+type reservation struct {
+	serverID  string
+	expiresAt time.Time
+}
+
+func newReservations() *reservations {
+	return &reservations{entries: make(map[string]reservation)}
+}
+
+// reserve reserves the floating IP for the server and returns false if it is reserved for another server.
+// A server holds a single reservation, so previous reservations of the server are released.
+func (r *reservations) reserve(fipID, serverID string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	now := time.Now()
+	if entry, ok := r.entries[fipID]; ok && entry.serverID != serverID && now.Before(entry.expiresAt) {
+		return false
+	}
+	for id, entry := range r.entries {
+		if entry.serverID == serverID || !now.Before(entry.expiresAt) {
+			delete(r.entries, id)
+		}
+	}
+	r.entries[fipID] = reservation{serverID: serverID, expiresAt: now.Add(reservationTimeout)}
+	return true
+}
+
+// release releases the reservation of the floating IP if it is held by the server.
+func (r *reservations) release(fipID, serverID string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if entry, ok := r.entries[fipID]; ok && entry.serverID == serverID {
+		delete(r.entries, fipID)
+	}
+}
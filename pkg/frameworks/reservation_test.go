@@ -0,0 +1,60 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+package frameworks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReservations(t *testing.T) {
+	r := newReservations()
+
+	if !r.reserve("fip-1", "server-1") {
+		t.Fatal("expected fip-1 to be reserved for server-1")
+	}
+	if r.reserve("fip-1", "server-2") {
+		t.Error("expected fip-1 not to be reserved for server-2 while server-1 holds it")
+	}
+	if !r.reserve("fip-1", "server-1") {
+		t.Error("expected fip-1 to be reserved again for server-1")
+	}
+
+	// A server holds a single reservation.
+	if !r.reserve("fip-2", "server-1") {
+		t.Fatal("expected fip-2 to be reserved for server-1")
+	}
+	if !r.reserve("fip-1", "server-2") {
+		t.Error("expected fip-1 to be released after server-1 reserved fip-2")
+	}
+
+	r.release("fip-2", "server-2")
+	if r.reserve("fip-2", "server-3") {
+		t.Error("expected fip-2 not to be released by another server")
+	}
+	r.release("fip-2", "server-1")
+	if !r.reserve("fip-2", "server-3") {
+		t.Error("expected fip-2 to be released by server-1")
+	}
+
+	r.entries["fip-2"] = reservation{serverID: "server-3", expiresAt: time.Now().Add(-time.Second)}
+	if !r.reserve("fip-2", "server-4") {
+		t.Error("expected expired reservation to be ignored")
+	}
+}
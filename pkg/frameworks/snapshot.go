@@ -0,0 +1,193 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+	"github.com/pkg/errors"
+)
+
+// snapshot holds the floating IPs allocated by the controller and the ports of the configured projects listed at once,
+// so workers can reconcile against it instead of requesting every floating IP and port individually.
+// Changes made by the controller are applied to the snapshot until it is refreshed.
+type snapshot struct {
+	mtx             sync.RWMutex
+	createdAt       time.Time
+	fipsByAddress   map[string]*neutronfip.FloatingIP
+	portsByID       map[string]*ports.Port
+	portsByDeviceID map[string][]*ports.Port
+}
+
+func newSnapshot(fips []neutronfip.FloatingIP, allPorts []ports.Port) *snapshot {
+	s := &snapshot{
+		createdAt:       time.Now(),
+		fipsByAddress:   make(map[string]*neutronfip.FloatingIP, len(fips)),
+		portsByID:       make(map[string]*ports.Port, len(allPorts)),
+		portsByDeviceID: make(map[string][]*ports.Port),
+	}
+	for i := range fips {
+		s.fipsByAddress[fips[i].FloatingIP] = &fips[i]
+	}
+	for i := range allPorts {
+		port := &allPorts[i]
+		s.portsByID[port.ID] = port
+		if port.DeviceID != "" {
+			s.portsByDeviceID[port.DeviceID] = append(s.portsByDeviceID[port.DeviceID], port)
+		}
+	}
+	return s
+}
+
+// RefreshSnapshot lists the floating IPs allocated by the controller and the ports of the configured projects and replaces the snapshot.
+// Does nothing unless --bulk-reconciliation is enabled.
+func (o *OSFramework) RefreshSnapshot(ctx context.Context) error {
+	if !o.opts.BulkReconciliation {
+		return nil
+	}
+
+	start := time.Now()
+	fips := make([]neutronfip.FloatingIP, 0)
+	allPorts := make([]ports.Port, 0)
+	for _, projectID := range o.opts.ProjectIDs {
+		listOpts := neutronfip.ListOpts{ProjectID: projectID}
+		if o.opts.ClusterName != "" {
+			listOpts.Tags = ClusterTag(o.opts.ClusterName)
+		}
+		projectFIPs, err := o.listFloatingIPs(ctx, listOpts)
+		if err != nil {
+			o.snapshot.Store(nil)
+			return errors.Wrapf(err, "failed to list floating ips of project %s", projectID)
+		}
+		for _, fip := range projectFIPs {
+			if IsCreatedByController(&fip) {
+				fips = append(fips, fip)
+			}
+		}
+
+		projectPorts, err := o.listPorts(ctx, ports.ListOpts{ProjectID: projectID})
+		if err != nil {
+			o.snapshot.Store(nil)
+			return errors.Wrapf(err, "failed to list ports of project %s", projectID)
+		}
+		allPorts = append(allPorts, projectPorts...)
+	}
+
+	o.snapshot.Store(newSnapshot(fips, allPorts))
+	//nolint:errcheck
+	_ = level.Info(o.logger).Log("msg", "refreshed snapshot", "fips", len(fips), "ports", len(allPorts), "projects", len(o.opts.ProjectIDs), "took", time.Since(start).String())
+	return nil
+}
+
+// getSnapshot returns the current snapshot or nil if there is none or it is outdated.
+func (o *OSFramework) getSnapshot() *snapshot {
+	s := o.snapshot.Load()
+	// A snapshot, which was not refreshed for two recheck intervals, is considered outdated, e.g. after failed refreshes.
+	if s == nil || time.Since(s.createdAt) > 2*o.opts.RecheckInterval {
+		return nil
+	}
+	return s
+}
+
+func (o *OSFramework) listPorts(ctx context.Context, listOpts ports.ListOpts) ([]ports.Port, error) {
+	allPages, err := ports.List(o.neutronClient(), listOpts).AllPages(withOperation(ctx, serviceNetwork, "list_ports"))
+	if err != nil {
+		return nil, err
+	}
+	return ports.ExtractPorts(allPages)
+}
+
+// getFloatingIP returns the floating IP with the given address.
+// The bool is false if the snapshot does not know a matching floating IP.
+func (s *snapshot) getFloatingIP(floatingIP, projectID string) (*neutronfip.FloatingIP, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	fip, ok := s.fipsByAddress[floatingIP]
+	if !ok || (projectID != "" && fip.ProjectID != projectID) {
+		return nil, false
+	}
+	return copyFloatingIP(fip), true
+}
+
+// getReusableFloatingIPs returns the floating IPs of the nodepool, which were not associated when the snapshot was listed.
+func (s *snapshot) getReusableFloatingIPs(projectID, nodepool string) []*neutronfip.FloatingIP {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	description := FloatingIPDescription(nodepool)
+	result := make([]*neutronfip.FloatingIP, 0)
+	for _, fip := range s.fipsByAddress {
+		if fip.Description == description && fip.FixedIP == "" && (projectID == "" || fip.ProjectID == projectID) {
+			result = append(result, copyFloatingIP(fip))
+		}
+	}
+	return result
+}
+
+func (s *snapshot) getPort(id string) (*ports.Port, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	port, ok := s.portsByID[id]
+	if !ok {
+		return nil, false
+	}
+	result := *port
+	return &result, true
+}
+
+// getPortOfDevice returns the first port of the server with the given ID.
+// The bool is false if the snapshot does not know any port of the server.
+func (s *snapshot) getPortOfDevice(deviceID string) (*ports.Port, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	devicePorts, ok := s.portsByDeviceID[deviceID]
+	if !ok {
+		return nil, false
+	}
+	result := *devicePorts[0]
+	return &result, true
+}
+
+// updateFloatingIP adds or replaces the floating IP after it was created or changed.
+func (s *snapshot) updateFloatingIP(fip *neutronfip.FloatingIP) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.fipsByAddress[fip.FloatingIP] = copyFloatingIP(fip)
+}
+
+// deleteFloatingIP removes the floating IP after it was deleted.
+func (s *snapshot) deleteFloatingIP(fip *neutronfip.FloatingIP) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.fipsByAddress, fip.FloatingIP)
+}
+
+func copyFloatingIP(fip *neutronfip.FloatingIP) *neutronfip.FloatingIP {
+	result := *fip
+	return &result
+}
@@ -0,0 +1,131 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+package frameworks
+
+import (
+	"slices"
+	"testing"
+
+	neutronfip "github.com/gophercloud/gophercloud/v2/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/v2/openstack/networking/v2/ports"
+)
+
+func newTestSnapshot() *snapshot {
+	return newSnapshot(
+		[]neutronfip.FloatingIP{
+			{ID: "fip-1", FloatingIP: "192.0.2.1", ProjectID: "project-1", PortID: "port-1", FixedIP: "10.0.0.1"},
+			{ID: "fip-2", FloatingIP: "192.0.2.2", ProjectID: "project-1", Description: FloatingIPDescription("pool-a")},
+			{ID: "fip-3", FloatingIP: "192.0.2.3", ProjectID: "project-2", Description: FloatingIPDescription("pool-b")},
+		},
+		[]ports.Port{
+			{ID: "port-1", DeviceID: "server-1"},
+		},
+	)
+}
+
+func TestSnapshotGetFloatingIP(t *testing.T) {
+	tests := map[string]struct {
+		floatingIP, projectID string
+		wantID                string
+	}{
+		"by address":                  {floatingIP: "192.0.2.1", projectID: "project-1", wantID: "fip-1"},
+		"by address in any project":   {floatingIP: "192.0.2.1", wantID: "fip-1"},
+		"by address in other project": {floatingIP: "192.0.2.1", projectID: "project-2"},
+		"unknown address":             {floatingIP: "192.0.2.99", projectID: "project-1"},
+	}
+
+	s := newTestSnapshot()
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fip, ok := s.getFloatingIP(tc.floatingIP, tc.projectID)
+			if tc.wantID == "" {
+				if ok {
+					t.Errorf("expected no FIP, got %s", fip.ID)
+				}
+				return
+			}
+			if !ok || fip.ID != tc.wantID {
+				t.Errorf("expected FIP %s, got %v", tc.wantID, fip)
+			}
+		})
+	}
+}
+
+func TestSnapshotGetReusableFloatingIPs(t *testing.T) {
+	tests := map[string]struct {
+		projectID, nodepool string
+		wantIDs             []string
+	}{
+		"nodepool":                  {projectID: "project-1", nodepool: "pool-a", wantIDs: []string{"fip-2"}},
+		"nodepool in any project":   {nodepool: "pool-b", wantIDs: []string{"fip-3"}},
+		"nodepool in other project": {projectID: "project-1", nodepool: "pool-b"},
+		"unknown nodepool":          {projectID: "project-1", nodepool: "pool-c"},
+	}
+
+	s := newTestSnapshot()
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ids := make([]string, 0)
+			for _, fip := range s.getReusableFloatingIPs(tc.projectID, tc.nodepool) {
+				ids = append(ids, fip.ID)
+			}
+			if !slices.Equal(ids, tc.wantIDs) {
+				t.Errorf("expected FIPs %v, got %v", tc.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestSnapshotTracksChanges(t *testing.T) {
+	s := newTestSnapshot()
+
+	fip, _ := s.getFloatingIP("192.0.2.2", "project-1")
+	fip.PortID = "port-2"
+	fip.FixedIP = "10.0.0.2"
+	s.updateFloatingIP(fip)
+	if fips := s.getReusableFloatingIPs("project-1", "pool-a"); len(fips) != 0 {
+		t.Error("expected associated FIP not to be reused")
+	}
+
+	s.updateFloatingIP(&neutronfip.FloatingIP{ID: "fip-4", FloatingIP: "192.0.2.4", ProjectID: "project-1"})
+	if _, ok := s.getFloatingIP("192.0.2.4", "project-1"); !ok {
+		t.Error("expected created FIP to be found")
+	}
+
+	s.deleteFloatingIP(&neutronfip.FloatingIP{FloatingIP: "192.0.2.1"})
+	if _, ok := s.getFloatingIP("192.0.2.1", "project-1"); ok {
+		t.Error("expected deleted FIP not to be found")
+	}
+
+	if port, ok := s.getPort("port-1"); !ok || port.DeviceID != "server-1" {
+		t.Errorf("expected port-1 of server-1, got %v", port)
+	}
+}
+
+func TestSnapshotGetPortOfDevice(t *testing.T) {
+	s := newTestSnapshot()
+
+	if port, ok := s.getPortOfDevice("server-1"); !ok || port.ID != "port-1" {
+		t.Errorf("expected port-1 of server-1, got %v", port)
+	}
+	if port, ok := s.getPortOfDevice("server-2"); ok {
+		t.Errorf("expected no port of server-2, got %v", port)
+	}
+}
@@ -0,0 +1,95 @@
+/*******************************************************************************
+*
+* Copyright 2026 SAP SE
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You should have received a copy of the License along with this
+* program. If not, you may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*
+*******************************************************************************/
+
+// Package health provides the liveness and readiness endpoints.
+package health
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Check returns an error if the checked component is not healthy.
+type Check func() error
+
+// Checker holds the liveness and readiness checks.
+type Checker struct {
+	mtx             sync.RWMutex
+	livenessChecks  map[string]Check
+	readinessChecks map[string]Check
+}
+
+// NewChecker returns a new Checker without any checks.
+func NewChecker() *Checker {
+	return &Checker{
+		livenessChecks:  make(map[string]Check),
+		readinessChecks: make(map[string]Check),
+	}
+}
+
+// AddLivenessCheck adds a check to the liveness endpoint.
+func (c *Checker) AddLivenessCheck(name string, check Check) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.livenessChecks[name] = check
+}
+
+// AddReadinessCheck adds a check to the readiness endpoint.
+func (c *Checker) AddReadinessCheck(name string, check Check) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.readinessChecks[name] = check
+}
+
+// LivenessHandler returns a handler responding with 200 if all liveness checks pass and 503 otherwise.
+func (c *Checker) LivenessHandler() http.Handler {
+	return c.handler(func() map[string]Check { return c.livenessChecks })
+}
+
+// ReadinessHandler returns a handler responding with 200 if all readiness checks pass and 503 otherwise.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return c.handler(func() map[string]Check { return c.readinessChecks })
+}
+
+func (c *Checker) handler(getChecks func() map[string]Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		c.mtx.RLock()
+		checks := maps.Clone(getChecks())
+		c.mtx.RUnlock()
+
+		status := http.StatusOK
+		lines := make([]string, 0, len(checks))
+		for _, name := range slices.Sorted(maps.Keys(checks)) {
+			if err := checks[name](); err != nil {
+				status = http.StatusServiceUnavailable
+				lines = append(lines, fmt.Sprintf("[-] %s failed: %s", name, err.Error()))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("[+] %s ok", name))
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = fmt.Fprintln(w, strings.Join(lines, "\n")) //nolint:errcheck
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/sapcc/kube-fip-controller/pkg/health"
 )
 
 const metricNamespace = "kube_fip_controller"
@@ -30,6 +32,92 @@ var (
 		Help:      "Counter for creating FIP errors.",
 	})
 
+	// MetricErrorDisassociateFIP ...
+	MetricErrorDisassociateFIP = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "disassociate_fip_errors_total",
+		Help:      "Counter for disassociating FIP errors.",
+	})
+
+	// MetricErrorDeleteFIP ...
+	MetricErrorDeleteFIP = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "delete_fip_errors_total",
+		Help:      "Counter for deleting FIP errors.",
+	})
+
+	// MetricOrphanedFIPs ...
+	MetricOrphanedFIPs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "orphaned_fips",
+		Help:      "Number of FIPs allocated by the controller that are not referenced by any node.",
+	})
+
+	// MetricLeader ...
+	MetricLeader = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "leader",
+		Help:      "Whether this replica is the leader. Always 1 if leader election is disabled.",
+	}, []string{"identity"})
+
+	// MetricNodeFIPInfo ...
+	MetricNodeFIPInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "node_fip_info",
+		Help:      "Information about the FIP assigned to a node.",
+	}, []string{"node", "fip", "network", "subnet", "nodepool", "server_id"})
+
+	// MetricNodeFIPAssociated ...
+	MetricNodeFIPAssociated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "node_fip_associated",
+		Help:      "Whether the FIP of a node is associated with its server.",
+	}, []string{"node"})
+
+	// MetricEnabledNodesWithoutFIP ...
+	MetricEnabledNodesWithoutFIP = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "enabled_nodes_without_fip",
+		Help:      "Number of enabled nodes without a FIP.",
+	})
+
+	// MetricOpenStackRequestDuration ...
+	MetricOpenStackRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Name:      "openstack_request_duration_seconds",
+		Help:      "Duration of requests to the OpenStack APIs.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "operation", "status"})
+
+	// MetricReconcileDuration ...
+	MetricReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of the reconciliation of a node.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"result"})
+
+	// MetricDryRunActions ...
+	MetricDryRunActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "dry_run_actions_total",
+		Help:      "Counter for actions skipped due to the dry run.",
+	}, []string{"action"})
+
+	// MetricCacheRequests ...
+	MetricCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "cache_requests_total",
+		Help:      "Counter for lookups in the caches of OpenStack resources.",
+	}, []string{"cache", "result"})
+
+	// MetricOpenStackThrottledRequests ...
+	MetricOpenStackThrottledRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "openstack_throttled_requests_total",
+		Help:      "Counter for requests to the OpenStack APIs that were throttled with 429.",
+	}, []string{"service"})
+
 	// MetricSuccessfulOperations ...
 	MetricSuccessfulOperations = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: metricNamespace,
@@ -48,13 +136,45 @@ func init() {
 	prometheus.MustRegister(
 		MetricErrorAssociateInstanceAndFIP,
 		MetricErrorCreateFIP,
+		MetricErrorDisassociateFIP,
+		MetricErrorDeleteFIP,
+		MetricOrphanedFIPs,
+		MetricLeader,
+		MetricNodeFIPInfo,
+		MetricNodeFIPAssociated,
+		MetricEnabledNodesWithoutFIP,
+		MetricOpenStackRequestDuration,
+		MetricReconcileDuration,
+		MetricDryRunActions,
+		MetricCacheRequests,
+		MetricOpenStackThrottledRequests,
 		MetricSuccessfulOperations,
 		MetricFailedOperations,
 	)
 }
 
-// ServeMetrics starts the Prometheus metrics collector.
-func ServeMetrics(host net.IP, port int, wg *sync.WaitGroup, stop <-chan struct{}, logger log.Logger) {
+// SetNodeFIPMetrics sets the FIP metrics of a node replacing previous values.
+func SetNodeFIPMetrics(node, fip, network, subnet, nodepool, serverID string, isAssociated bool) {
+	MetricNodeFIPInfo.DeletePartialMatch(prometheus.Labels{"node": node})
+	if fip != "" {
+		MetricNodeFIPInfo.WithLabelValues(node, fip, network, subnet, nodepool, serverID).Set(1)
+	}
+
+	associated := 0.0
+	if isAssociated {
+		associated = 1
+	}
+	MetricNodeFIPAssociated.WithLabelValues(node).Set(associated)
+}
+
+// DeleteNodeFIPMetrics removes the FIP metrics of a node.
+func DeleteNodeFIPMetrics(node string) {
+	MetricNodeFIPInfo.DeletePartialMatch(prometheus.Labels{"node": node})
+	MetricNodeFIPAssociated.DeleteLabelValues(node)
+}
+
+// ServeMetrics starts the Prometheus metrics collector and the health endpoints.
+func ServeMetrics(host net.IP, port int, healthChecker *health.Checker, wg *sync.WaitGroup, stop <-chan struct{}, logger log.Logger) {
 	wg.Add(1)
 	defer wg.Done()
 
@@ -77,11 +197,16 @@ func ServeMetrics(host net.IP, port int, wg *sync.WaitGroup, stop <-chan struct{
 	//nolint:errcheck
 	_ = level.Info(logger).Log("msg", "serving prometheus metrics", "address", addr, "path", "/metrics")
 
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/healthz", healthChecker.LivenessHandler())
+	mux.Handle("/readyz", healthChecker.ReadinessHandler())
+
 	go func() {
 		server := &http.Server{
 			ReadHeaderTimeout: 5 * time.Second,
 		}
-		server.Handler = promhttp.Handler()
+		server.Handler = mux
 		err = server.Serve(l)
 		if err != nil {
 			//nolint:errcheck